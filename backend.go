@@ -0,0 +1,466 @@
+package mutterblack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// Backend stores the data of plugins by name, the bot uses one for Persister data and plugin storage.
+type Backend interface {
+	// Read returns the data saved under name, or nil if there is none.
+	Read(name string) ([]byte, error)
+	// Write saves data under name, replacing what was there.
+	Write(name string, data []byte) error
+	// Delete removes the data saved under name, deleting a name that doesn't exist does nothing.
+	Delete(name string) error
+	// List returns every name that has data, in sorted order.
+	List() ([]string, error)
+	// Close releases the backend, it can't be used afterwards.
+	Close() error
+}
+
+// Backup is an older version of saved data.
+type Backup struct {
+	Data  []byte
+	Saved time.Time
+}
+
+// BackupReader is implemented by backends that keep older versions of what they save.
+type BackupReader interface {
+	// Backups returns the backups of name, newest first.
+	Backups(name string) ([]Backup, error)
+}
+
+// BackupRemover is implemented by backends that keep older versions of what they save, so data deleted on request can be removed from them too.
+type BackupRemover interface {
+	// RemoveBackups deletes every backup of name, the current data is kept.
+	RemoveBackups(name string) error
+}
+
+const (
+	// BackendFile keeps every name in its own file of a directory.
+	BackendFile = "file"
+	// BackendBolt keeps every name in a BoltDB database file.
+	BackendBolt = "bolt"
+	// BackendCore keeps every name in the core service.
+	BackendCore = "core"
+)
+
+const (
+	defaultDataDirectory = "data"
+	defaultBoltPath      = "mutterblack.db"
+	defaultBackupCount   = 3
+	backupDirectory      = "backups"
+
+	// defaultBackupInterval is how often a new backup is made, data is saved seconds after it changes so a backup of every save would only reach back a few seconds.
+	defaultBackupInterval = time.Hour
+)
+
+// NewBackend opens a backend of kind, path is the directory of a file backend or the database of a bolt backend and can be empty for the default.
+// A core backend saves through core.
+func NewBackend(kind string, path string, core *CoreClient) (Backend, error) {
+	switch strings.ToLower(kind) {
+	case "", BackendFile:
+		if path == "" {
+			path = defaultDataDirectory
+		}
+		return NewFileBackend(path), nil
+	case BackendBolt:
+		if path == "" {
+			path = defaultBoltPath
+		}
+		return NewBoltBackend(path)
+	case BackendCore:
+		return NewCoreBackend(core), nil
+	}
+	return nil, fmt.Errorf("unknown storage backend %q, expected %s, %s or %s", kind, BackendFile, BackendBolt, BackendCore)
+}
+
+// BackendFromEnvironment opens the backend named by STORAGE_BACKEND at STORAGE_PATH, it defaults to files in data/.
+// STORAGE_BACKUPS sets how many backups the file backend keeps of each name.
+func BackendFromEnvironment(core *CoreClient) (Backend, error) {
+	backend, err := NewBackend(os.Getenv("STORAGE_BACKEND"), os.Getenv("STORAGE_PATH"), core)
+	if err != nil {
+		return nil, err
+	}
+
+	if fileBackend, ok := backend.(*FileBackend); ok {
+		if count, err := strconv.Atoi(os.Getenv("STORAGE_BACKUPS")); err == nil && count >= 0 {
+			fileBackend.BackupCount = count
+		}
+	}
+	return backend, nil
+}
+
+// MigrateBackend copies everything saved in from to to, it returns the names that were copied.
+func MigrateBackend(from Backend, to Backend) ([]string, error) {
+	names, err := from.List()
+	if err != nil {
+		return nil, err
+	}
+
+	copied := []string{}
+	for _, name := range names {
+		data, err := from.Read(name)
+		if err != nil {
+			return copied, fmt.Errorf("reading %s: %v", name, err)
+		}
+		if data == nil {
+			continue
+		}
+		if err := to.Write(name, data); err != nil {
+			return copied, fmt.Errorf("writing %s: %v", name, err)
+		}
+		copied = append(copied, name)
+	}
+
+	return copied, nil
+}
+
+// FileBackend keeps every name in its own file of a directory, it is how the bot has always saved data.
+// Files are replaced atomically, and BackupCount versions of each file, at most one per BackupInterval, are kept in a backups directory.
+type FileBackend struct {
+	Directory      string
+	BackupCount    int
+	BackupInterval time.Duration
+}
+
+// NewFileBackend returns a backend keeping files in directory.
+func NewFileBackend(directory string) *FileBackend {
+	return &FileBackend{
+		Directory:      directory,
+		BackupCount:    defaultBackupCount,
+		BackupInterval: defaultBackupInterval,
+	}
+}
+
+func (f *FileBackend) path(name string) string {
+	return filepath.Join(f.Directory, name)
+}
+
+func (f *FileBackend) backupPath(name string, i int) string {
+	return filepath.Join(f.Directory, backupDirectory, fmt.Sprintf("%s.%d", name, i))
+}
+
+func (f *FileBackend) Read(name string) ([]byte, error) {
+	data, err := ioutil.ReadFile(f.path(name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Write writes data to a temporary file that is synced and renamed over name, so a crash never leaves a partly written file.
+// The file it replaces becomes the newest backup if the newest one is older than BackupInterval.
+func (f *FileBackend) Write(name string, data []byte) error {
+	if err := os.MkdirAll(f.Directory, 0755); err != nil {
+		return err
+	}
+
+	temp, err := ioutil.TempFile(f.Directory, "."+name+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), 0644); err != nil {
+		return err
+	}
+
+	if err := f.rotateBackups(name); err != nil {
+		return err
+	}
+
+	if err := os.Rename(temp.Name(), f.path(name)); err != nil {
+		return err
+	}
+	return syncDirectory(f.Directory)
+}
+
+// rotateBackups shifts the backups of name by one, dropping the oldest, and makes the current file the newest backup.
+// Nothing is done while the newest backup is younger than BackupInterval.
+func (f *FileBackend) rotateBackups(name string) error {
+	if f.BackupCount <= 0 {
+		return nil
+	}
+
+	current := f.path(name)
+	if _, err := os.Stat(current); os.IsNotExist(err) {
+		return nil
+	}
+
+	if info, err := os.Stat(f.backupPath(name, 1)); err == nil && time.Since(info.ModTime()) < f.BackupInterval {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(f.Directory, backupDirectory), 0755); err != nil {
+		return err
+	}
+
+	if err := os.Remove(f.backupPath(name, f.BackupCount)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := f.BackupCount - 1; i >= 1; i-- {
+		if err := os.Rename(f.backupPath(name, i), f.backupPath(name, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	// The current file is linked rather than moved, so name always exists until the new version replaces it.
+	if err := os.Link(current, f.backupPath(name, 1)); err == nil {
+		return nil
+	}
+	data, err := ioutil.ReadFile(current)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.backupPath(name, 1), data, 0644)
+}
+
+// Backups returns the backups of name, newest first.
+func (f *FileBackend) Backups(name string) ([]Backup, error) {
+	backups := []Backup{}
+	for i := 1; i <= f.BackupCount; i++ {
+		path := f.backupPath(name, i)
+
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return backups, err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return backups, err
+		}
+		backups = append(backups, Backup{Data: data, Saved: info.ModTime()})
+	}
+	return backups, nil
+}
+
+// RemoveBackups deletes every backup of name, including those kept while BackupCount was higher.
+func (f *FileBackend) RemoveBackups(name string) error {
+	files, err := ioutil.ReadDir(filepath.Join(f.Directory, backupDirectory))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), name+".") {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimPrefix(file.Name(), name+".")); err != nil {
+			continue
+		}
+		if err := os.Remove(filepath.Join(f.Directory, backupDirectory, file.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes name and its backups.
+func (f *FileBackend) Delete(name string) error {
+	if err := os.Remove(f.path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return f.RemoveBackups(name)
+}
+
+// List returns the name of every file, leaving out backups and temporary files.
+func (f *FileBackend) List() ([]string, error) {
+	files, err := ioutil.ReadDir(f.Directory)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, file := range files {
+		if file.Mode().IsRegular() && !strings.HasPrefix(file.Name(), ".") {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (f *FileBackend) Close() error {
+	return nil
+}
+
+// syncDirectory flushes a directory, so a file renamed into it survives a crash.
+func syncDirectory(directory string) error {
+	d, err := os.Open(directory)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	// Some platforms can't sync directories, the rename has still happened.
+	d.Sync()
+	return nil
+}
+
+var boltBucket = []byte("plugins")
+
+// BoltBackend keeps every name in one bucket of a BoltDB database.
+type BoltBackend struct {
+	db *bolt.DB
+}
+
+// NewBoltBackend opens the database at path, creating it if needed.
+// Only one process can have the database open, so it must be closed before it is migrated.
+func NewBoltBackend(path string) (*BoltBackend, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltBackend{db: db}, nil
+}
+
+func (b *BoltBackend) Read(name string) ([]byte, error) {
+	var data []byte
+	err := b.db.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(boltBucket).Get([]byte(name)); value != nil {
+			data = append([]byte{}, value...)
+		}
+		return nil
+	})
+	return data, err
+}
+
+func (b *BoltBackend) Write(name string, data []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(name), data)
+	})
+}
+
+func (b *BoltBackend) Delete(name string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(name))
+	})
+}
+
+func (b *BoltBackend) List() ([]string, error) {
+	names := []string{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).ForEach(func(key, value []byte) error {
+			names = append(names, string(key))
+			return nil
+		})
+	})
+	return names, err
+}
+
+func (b *BoltBackend) Close() error {
+	return b.db.Close()
+}
+
+// CoreBackend keeps every name in the core service, so several bot instances can share data.
+type CoreBackend struct {
+	core *CoreClient
+}
+
+// coreData is how data is sent to and returned by the core service.
+type coreData struct {
+	Key  string `json:"key"`
+	Data []byte `json:"data"`
+}
+
+// NewCoreBackend returns a backend that saves data through core.
+// A bot opened with a core backend makes it use the bot's Core instead.
+func NewCoreBackend(core *CoreClient) *CoreBackend {
+	return &CoreBackend{core: core}
+}
+
+func (c *CoreBackend) path(name string) string {
+	return "data/discord/" + name
+}
+
+func (c *CoreBackend) Read(name string) ([]byte, error) {
+	resp, err := c.core.Get(c.path(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var stored *coreData
+	if len(resp) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(resp, &stored); err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, nil
+	}
+	return stored.Data, nil
+}
+
+func (c *CoreBackend) Write(name string, data []byte) error {
+	_, err := c.core.Post(c.path(name), coreData{Key: name, Data: data})
+	return err
+}
+
+func (c *CoreBackend) Delete(name string) error {
+	_, err := c.core.Delete(c.path(name))
+	return err
+}
+
+func (c *CoreBackend) List() ([]string, error) {
+	resp, err := c.core.Get("data/discord")
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	if err := json.Unmarshal(resp, &names); err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (c *CoreBackend) Close() error {
+	return nil
+}
@@ -1,21 +1,55 @@
 package mutterblack
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"os"
 	"regexp"
 	"runtime/debug"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
 )
 
 const VersionString string = "2.0.0"
 
 type Bot struct {
-	Client          *Discord
-	Plugins         map[string]Plugin
+	Client  *Discord
+	Plugins map[string]Plugin
+	// Backend is where plugin data is saved, it defaults to files in data/.
+	// A CoreBackend saves through Core once the bot is opened.
+	Backend Backend
+	// Core is the client of the core service, it defaults to the core described by the environment and can be replaced before Open.
+	Core            *CoreClient
 	messageChannels []chan Message
+	pluginOrder     []string
+
+	lifecycleLock sync.Mutex
+	ready         chan struct{}
+	readyShards   map[int]bool
+	// knownGuilds are the guilds the bot is in, GuildJoin is only called for guilds that are new to it.
+	knownGuilds map[string]bool
+	// pendingGuilds are the guilds created on a shard before its first Ready was handled, discordgo runs handlers concurrently.
+	pendingGuilds map[int][]*discordgo.Guild
+
+	cooldownsLock  sync.Mutex
+	cooldowns      map[string]time.Time
+	cooldownsSwept time.Time
+
+	storesLock sync.Mutex
+	stores     map[string]*pluginStore
+
+	// SaveDelay is how long changes are collected before they are saved, it defaults to 10 seconds.
+	SaveDelay time.Duration
+	saves     *saveState
+
+	// PanicChannelID is where plugin panics and data that failed to load are reported, they are sent to the owner privately when it is empty.
+	PanicChannelID string
+	panics         *panicReporter
 }
 
 func MessageRecover() {
@@ -31,8 +65,26 @@ func NewBot(token string, clientId string, ownerUserId string) *Bot {
 	}
 
 	bot := &Bot{
-		Plugins: make(map[string]Plugin, 0),
-		Client:  NewDiscord("Bot " + token),
+		Plugins:   make(map[string]Plugin, 0),
+		Client:    NewDiscord("Bot " + token),
+		cooldowns: make(map[string]time.Time),
+		stores:    make(map[string]*pluginStore),
+		Backend:   NewFileBackend(defaultDataDirectory),
+		Core:      CoreClientFromEnvironment(),
+		SaveDelay: defaultSaveDelay,
+		saves: &saveState{
+			dirty: make(map[string]bool),
+		},
+
+		ready:       make(chan struct{}),
+		readyShards: make(map[int]bool),
+		knownGuilds: make(map[string]bool),
+
+		pendingGuilds: make(map[int][]*discordgo.Guild),
+
+		panics: &panicReporter{
+			reports: make(map[string]*panicReport),
+		},
 	}
 
 	bot.Client.ApplicationClientID = clientId
@@ -41,58 +93,147 @@ func NewBot(token string, clientId string, ownerUserId string) *Bot {
 	return bot
 }
 
-func (b *Bot) getData(plugin Plugin) []byte {
-	if b, err := ioutil.ReadFile("data/" + plugin.Name()); err == nil {
-		return b
+// loadData calls load with the data saved under name.
+// When the data can't be read, isn't valid JSON or load fails, the newest backup that loads is used instead and the owner is warned.
+func (b *Bot) loadData(name string, load func(data []byte) error) {
+	data, err := b.Backend.Read(name)
+	if err == nil && data != nil && !json.Valid(data) {
+		err = errors.New("invalid JSON")
+	}
+	if err == nil {
+		if err = load(data); err == nil {
+			return
+		}
+	}
+
+	log.Printf("Error loading %s. %v", name, err)
+
+	l := NewLocalizer(DefaultLocale)
+
+	if reader, ok := b.Backend.(BackupReader); ok {
+		backups, backupErr := reader.Backups(name)
+		if backupErr != nil {
+			log.Printf("Error reading backups of %s. %v", name, backupErr)
+		}
+
+		for _, backup := range backups {
+			if !json.Valid(backup.Data) || load(backup.Data) != nil {
+				continue
+			}
+
+			log.Printf("Loaded %s from the backup saved at %v", name, backup.Saved)
+			b.notifyOwner([]string{l.T("storage.backup-loaded", name, err, backup.Saved.UTC().Format(time.RFC1123))})
+			return
+		}
 	}
-	return nil
+
+	b.notifyOwner([]string{l.T("storage.load-failed", name, err)})
+}
+
+// writeData saves data under name.
+func (b *Bot) writeData(name string, data []byte) error {
+	return b.Backend.Write(name, data)
 }
 
 func (b *Bot) RegisterPlugin(plugin Plugin) {
 	if b.Plugins[plugin.Name()] != nil {
 		log.Println("Plugin with that name already registered", plugin.Name())
+	} else {
+		b.pluginOrder = append(b.pluginOrder, plugin.Name())
 	}
 	b.Plugins[plugin.Name()] = plugin
 }
 
-func (b *Bot) listen(messageChan <-chan Message) {
+func (b *Bot) listen(messageChan <-chan Message, eventChan <-chan interface{}) {
 	log.Printf("Listening")
 	for {
-		message := <-messageChan
-		plugins := b.Plugins
-		for _, plugin := range plugins {
-			go plugin.Message(b, b.Client, message)
-			if !b.Client.IsMe(message) {
-				go findCommandMatch(b, plugin, message)
-			}
+		select {
+		case message := <-messageChan:
+			go b.dispatchMessage(message)
+		case event := <-eventChan:
+			b.dispatchEvent(event)
 		}
 	}
 }
 
-func findCommandMatch(b *Bot, plugin Plugin, message Message) {
-	defer MessageRecover()
+// messagePlugins returns the registered plugins in the order they see messages, by priority and then registration order.
+func (b *Bot) messagePlugins() []Plugin {
+	plugins := b.orderedPlugins(false)
+	sort.SliceStable(plugins, func(i, j int) bool {
+		return PluginPriority(plugins[i]) > PluginPriority(plugins[j])
+	})
+	return plugins
+}
+
+// dispatchMessage passes a message to every plugin by priority, then matches it against the commands of every plugin.
+// A MessageHandler can stop the message from going any further, so handlers are waited for one after the other.
+// Listeners can't stop a message, each is started in its own goroutine so a slow one holds up neither later plugins nor commands.
+func (b *Bot) dispatchMessage(message Message) {
+	config := b.Client.messageGuildConfiguration(message)
+	plugins := b.messagePlugins()
+
+	for _, plugin := range plugins {
+		if b.pluginDisabled(plugin, config) || b.maintenance(plugin, nil) != nil {
+			continue
+		}
+
+		stopped := false
+		if handler, ok := plugin.(MessageHandler); ok {
+			b.protect(plugin, "Message", message.Message(), func() {
+				stopped = handler.HandleMessage(b, b.Client, message)
+			})
+		} else if listener, ok := plugin.(MessageListener); ok {
+			go b.protect(plugin, "Message", message.Message(), func() {
+				listener.Message(b, b.Client, message)
+			})
+		}
+
+		if stopped {
+			return
+		}
+	}
 
-	if plugin.Commands() == nil || message.Message() == "" {
+	if b.Client.IsMe(message) {
 		return
 	}
 
-	for _, commandDefinition := range plugin.Commands() {
+	for _, plugin := range plugins {
+		if b.pluginDisabled(plugin, config) {
+			continue
+		}
+
+		plugin := plugin
+		go b.protect(plugin, "Commands", message.Message(), func() {
+			findCommandMatch(b, plugin, message)
+		})
+	}
+}
+
+func findCommandMatch(b *Bot, plugin Plugin, message Message) {
+	commands := PluginCommands(plugin)
+	if commands == nil || message.Message() == "" {
+		return
+	}
+
+	prefix := b.Client.MessageCommandPrefix(message)
+
+	for _, commandDefinition := range commands {
 		for _, trigger := range commandDefinition.Triggers {
-			var trig = b.Client.CommandPrefix() + trigger
+			var trig = prefix + trigger
 			var parts = strings.Split(message.Message(), " ")
 
 			if parts[0] == trig {
 				log.Printf("<%s> %s: %s\n", message.Channel(), message.UserName(), message.Message())
 
 				if commandDefinition.Arguments == nil {
-					commandDefinition.Callback(b, b.Client, message, nil, trigger)
+					b.runCommand(plugin, &commandDefinition, message, nil, trigger)
 					return
 				}
 
 				parsedArgs := extractCommandArguments(message, trig, commandDefinition.Arguments)
 
 				if parsedArgs != nil {
-					commandDefinition.Callback(b, b.Client, message, parsedArgs, trigger)
+					b.runCommand(plugin, &commandDefinition, message, parsedArgs, trigger)
 					return
 				}
 			}
@@ -100,42 +241,98 @@ func findCommandMatch(b *Bot, plugin Plugin, message Message) {
 	}
 }
 
+func (b *Bot) runCommand(plugin Plugin, commandDefinition *CommandDefinition, message Message, args map[string]string, trigger string) {
+	if entry := b.maintenance(plugin, commandDefinition); entry != nil {
+		b.Client.SendMessage(message.Channel(), entry.notice(b.Localizer(message)))
+		return
+	}
+
+	if reason := b.commandDenied(plugin, commandDefinition, message); reason != "" {
+		b.Client.SendMessage(message.Channel(), reason)
+		return
+	}
+
+	if reason := b.commandCooldown(commandDefinition, message); reason != "" {
+		b.Client.SendMessage(message.Channel(), reason)
+		return
+	}
+
+	b.protect(plugin, commandDefinition.CommandID, message.Message(), func() {
+		commandDefinition.Callback(b, b.Client, message, args, trigger)
+	})
+}
+
+// Open connects to Discord and loads every plugin in registration order.
+// Plugins implementing ReadyHandler are started once every shard is connected.
 func (b *Bot) Open() {
+	b.Client.core = b.Core
+	if backend, ok := b.Backend.(*CoreBackend); ok {
+		backend.core = b.Core
+	}
+
+	b.Client.AddHandler(b.onReady)
+	b.Client.AddHandler(b.onGuildCreate)
+	b.Client.AddHandler(b.onGuildDelete)
+
 	if messageChan, err := b.Client.Open(); err == nil {
-		for _, plugin := range b.Plugins {
-			plugin.Load(b, b.Client, b.getData(plugin))
+		for _, plugin := range b.orderedPlugins(false) {
+			if persister, ok := plugin.(Persister); ok {
+				b.protect(plugin, "Load", "", func() {
+					b.loadData(plugin.Name(), func(data []byte) error {
+						migrated, err := migrateData(plugin, data)
+						if err != nil {
+							return err
+						}
+						return persister.Load(b, b.Client, migrated)
+					})
+				})
+			}
 		}
-		go b.listen(messageChan)
+		go b.listen(messageChan, b.Client.Events())
+		go b.waitReady()
 	} else {
 		log.Printf("Error creating discord service: %v\n", err)
 	}
 }
 
-func (b *Bot) Save() {
-	if err := os.Mkdir("data", os.ModePerm); err != nil {
-		if !os.IsExist(err) {
-			log.Println("Error creating service directory.")
+// Close stops every plugin implementing ShutdownHandler in reverse registration order, stops scheduled saves, saves all plugins, closes the backend and disconnects.
+// Shutdown errors are logged and returned after the bot has been saved and disconnected.
+func (b *Bot) Close() error {
+	shutdownErr := b.runLifecycle("Shutdown", true, func(plugin Plugin) error {
+		if handler, ok := plugin.(ShutdownHandler); ok {
+			return handler.Shutdown(b, b.Client)
 		}
+		return nil
+	})
+
+	b.closeSaves()
+	b.Save()
+
+	if err := b.Backend.Close(); err != nil {
+		log.Printf("Error closing storage backend. %v", err)
 	}
-	for _, plugin := range b.Plugins {
-		if data, err := plugin.Save(); err != nil {
-			log.Printf("Error saving plugin %s. %v", plugin.Name(), err)
-		} else if data != nil {
-			if err := ioutil.WriteFile("data/"+plugin.Name(), data, os.ModePerm); err != nil {
-				log.Printf("Error saving plugin %s. %v", plugin.Name(), err)
-			}
-		}
+
+	if err := b.Client.Close(); err != nil && shutdownErr == nil {
+		return err
 	}
+	return shutdownErr
 }
 
 func extractCommandArguments(message Message, trigger string, arguments []CommandDefinitionArgument) map[string]string {
 	var argPatterns []string
-	for _, argument := range arguments {
-		argPatterns = append(argPatterns, fmt.Sprintf("(?P<%s>%s)", argument.Alias, argument.Pattern))
+	for i, argument := range arguments {
+		argPattern := fmt.Sprintf("(?P<%s>%s)", argument.Alias, argument.Pattern)
+		if i > 0 {
+			argPattern = " " + argPattern
+		}
+		if argument.Optional {
+			argPattern = fmt.Sprintf("(?:%s)?", argPattern)
+		}
+		argPatterns = append(argPatterns, argPattern)
 	}
-	var pattern = fmt.Sprintf("^%s$", strings.Join(argPatterns, " "))
+	var pattern = fmt.Sprintf("^%s$", strings.Join(argPatterns, ""))
 
-	var trimmedContent = strings.TrimPrefix(message.Message(), fmt.Sprintf("%s ", trigger))
+	var trimmedContent = strings.TrimPrefix(strings.TrimPrefix(message.Message(), trigger), " ")
 	pat := regexp.MustCompile(pattern)
 	argsMatch := pat.FindStringSubmatch(trimmedContent)
 
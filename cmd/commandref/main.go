@@ -0,0 +1,267 @@
+// Command commandref generates the command reference from the registered plugins.
+//
+// It writes Markdown to stdout by default:
+//
+//	go run ./cmd/commandref -markdown docs/commands.md -json docs/commands.json
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lampjaw/mutterblack.discord"
+	"github.com/lampjaw/mutterblack.discord/plugins"
+)
+
+type argumentReference struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Optional    bool   `json:"optional"`
+	Description string `json:"description,omitempty"`
+}
+
+type commandReference struct {
+	ID          string              `json:"id,omitempty"`
+	Usage       string              `json:"usage"`
+	Description string              `json:"description"`
+	Aliases     []string            `json:"aliases,omitempty"`
+	Arguments   []argumentReference `json:"arguments,omitempty"`
+	Examples    []string            `json:"examples,omitempty"`
+	Permissions []string            `json:"permissions,omitempty"`
+	Cooldown    string              `json:"cooldown,omitempty"`
+}
+
+type pluginReference struct {
+	Name     string             `json:"name"`
+	Commands []commandReference `json:"commands"`
+}
+
+// referenceMessage stands in for a message from a user with no guild, so legacy help can be rendered without a session.
+type referenceMessage struct{}
+
+func (m referenceMessage) Channel() string               { return "" }
+func (m referenceMessage) UserName() string              { return "" }
+func (m referenceMessage) UserID() string                { return "" }
+func (m referenceMessage) UserAvatar() string            { return "" }
+func (m referenceMessage) Message() string               { return "" }
+func (m referenceMessage) RawMessage() string            { return "" }
+func (m referenceMessage) MessageID() string             { return "" }
+func (m referenceMessage) Type() mutterblack.MessageType { return mutterblack.MessageTypeCreate }
+func (m referenceMessage) Timestamp() (time.Time, error) { return time.Now(), nil }
+
+func main() {
+	markdownPath := flag.String("markdown", "", "write the Markdown reference to this file")
+	jsonPath := flag.String("json", "", "write the JSON reference to this file")
+	flag.Parse()
+
+	bot := mutterblack.NewBot("reference", "", "")
+
+	commandPlugin := mutterblack.NewCommandPlugin()
+	plugins.AddCommands(commandPlugin)
+
+	bot.RegisterPlugin(commandPlugin)
+	for _, plugin := range plugins.New() {
+		bot.RegisterPlugin(plugin)
+	}
+
+	reference := buildReference(bot)
+
+	if *markdownPath == "" && *jsonPath == "" {
+		os.Stdout.Write(renderMarkdown(reference))
+		return
+	}
+
+	if *markdownPath != "" {
+		if err := ioutil.WriteFile(*markdownPath, renderMarkdown(reference), 0644); err != nil {
+			log.Fatalln("Error writing markdown reference", err)
+		}
+	}
+
+	if *jsonPath != "" {
+		b, err := json.MarshalIndent(reference, "", "  ")
+		if err != nil {
+			log.Fatalln("Error encoding json reference", err)
+		}
+		if err := ioutil.WriteFile(*jsonPath, append(b, '\n'), 0644); err != nil {
+			log.Fatalln("Error writing json reference", err)
+		}
+	}
+}
+
+func buildReference(bot *mutterblack.Bot) []pluginReference {
+	client := bot.Client
+	message := referenceMessage{}
+	prefix := client.CommandPrefix()
+	l := mutterblack.NewLocalizer(mutterblack.DefaultLocale)
+
+	names := []string{}
+	for name := range bot.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reference := []pluginReference{}
+
+	for _, name := range names {
+		plugin := bot.Plugins[name]
+		commands := []commandReference{}
+		triggers := map[string]bool{}
+
+		for _, commandDefinition := range mutterblack.PluginCommands(plugin) {
+			commands = append(commands, definitionReference(commandDefinition, prefix, l))
+			for _, trigger := range commandDefinition.Triggers {
+				triggers[strings.ToLower(trigger)] = true
+			}
+		}
+
+		// Legacy help lines are kept unless they describe a trigger that already has a command definition.
+		legacy := []commandReference{}
+		for _, line := range mutterblack.PluginHelp(bot, client, message, plugin, false) {
+			command, ok := legacyReference(line, prefix)
+			if !ok || triggers[strings.ToLower(strings.TrimPrefix(strings.Fields(command.Usage)[0], prefix))] {
+				continue
+			}
+			legacy = append(legacy, command)
+		}
+		sort.Slice(legacy, func(i, j int) bool { return legacy[i].Usage < legacy[j].Usage })
+		commands = append(commands, legacy...)
+
+		if len(commands) > 0 {
+			reference = append(reference, pluginReference{
+				Name:     name,
+				Commands: commands,
+			})
+		}
+	}
+
+	return reference
+}
+
+func definitionReference(commandDefinition mutterblack.CommandDefinition, prefix string, l *mutterblack.Localizer) commandReference {
+	command := commandReference{
+		ID:          commandDefinition.CommandID,
+		Usage:       commandDefinition.Usage(prefix),
+		Description: l.T(commandDefinition.Description),
+		Permissions: commandDefinition.Restrictions(l),
+	}
+
+	for _, trigger := range commandDefinition.Triggers[1:] {
+		command.Aliases = append(command.Aliases, prefix+trigger)
+	}
+
+	for _, argument := range commandDefinition.Arguments {
+		if argument.Type == mutterblack.ArgumentTypeKeyword {
+			continue
+		}
+
+		argumentType := argument.Type
+		if argumentType == "" {
+			argumentType = mutterblack.ArgumentTypeText
+		}
+
+		description := ""
+		if argument.Description != "" {
+			description = l.T(argument.Description)
+		}
+
+		command.Arguments = append(command.Arguments, argumentReference{
+			Name:        argument.Alias,
+			Type:        string(argumentType),
+			Optional:    argument.Optional,
+			Description: description,
+		})
+	}
+
+	for _, example := range commandDefinition.Examples {
+		command.Examples = append(command.Examples, fmt.Sprintf("%s%s %s", prefix, commandDefinition.Triggers[0], example))
+	}
+
+	if commandDefinition.Cooldown > 0 {
+		command.Cooldown = commandDefinition.Cooldown.String()
+	}
+
+	return command
+}
+
+// legacyReference parses a line created by mutterblack.CommandHelp, eg. "`?stats` - Lists bot statistics.".
+func legacyReference(line string, prefix string) (commandReference, bool) {
+	if !strings.HasPrefix(line, "`"+prefix) {
+		return commandReference{}, false
+	}
+
+	end := strings.Index(line[1:], "`")
+	if end < 0 {
+		return commandReference{}, false
+	}
+
+	return commandReference{
+		Usage:       line[1 : end+1],
+		Description: strings.TrimPrefix(line[end+2:], " - "),
+	}, true
+}
+
+func renderMarkdown(reference []pluginReference) []byte {
+	b := &bytes.Buffer{}
+
+	fmt.Fprintln(b, "# Command reference")
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "Generated by `go run ./cmd/commandref`, do not edit by hand.")
+
+	for _, plugin := range reference {
+		fmt.Fprintln(b)
+		fmt.Fprintf(b, "## %s\n", plugin.Name)
+
+		for _, command := range plugin.Commands {
+			fmt.Fprintln(b)
+			fmt.Fprintf(b, "### `%s`\n", command.Usage)
+			fmt.Fprintln(b)
+			fmt.Fprintln(b, command.Description)
+
+			details := []string{}
+			if command.ID != "" {
+				details = append(details, fmt.Sprintf("- Command ID: `%s`", command.ID))
+			}
+			if len(command.Aliases) > 0 {
+				details = append(details, fmt.Sprintf("- Aliases: `%s`", strings.Join(command.Aliases, "`, `")))
+			}
+			if len(command.Arguments) > 0 {
+				details = append(details, "- Arguments:")
+				for _, argument := range command.Arguments {
+					requirement := "required"
+					if argument.Optional {
+						requirement = "optional"
+					}
+					line := fmt.Sprintf("  - `%s` (%s, %s)", argument.Name, argument.Type, requirement)
+					if argument.Description != "" {
+						line = fmt.Sprintf("%s - %s", line, argument.Description)
+					}
+					details = append(details, line)
+				}
+			}
+			if len(command.Examples) > 0 {
+				details = append(details, fmt.Sprintf("- Examples: `%s`", strings.Join(command.Examples, "`, `")))
+			}
+			if len(command.Permissions) > 0 {
+				details = append(details, fmt.Sprintf("- Permissions: %s", strings.Join(command.Permissions, ", ")))
+			}
+			if command.Cooldown != "" {
+				details = append(details, fmt.Sprintf("- Cooldown: %s", command.Cooldown))
+			}
+
+			if len(details) > 0 {
+				fmt.Fprintln(b)
+				fmt.Fprintln(b, strings.Join(details, "\n"))
+			}
+		}
+	}
+
+	return b.Bytes()
+}
@@ -4,25 +4,22 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"time"
 
 	"github.com/lampjaw/mutterblack.discord"
-	"github.com/lampjaw/mutterblack.discord/plugins/inviteplugin"
-	"github.com/lampjaw/mutterblack.discord/plugins/planetsidetwoplugin"
-	"github.com/lampjaw/mutterblack.discord/plugins/statsplugin"
-	"github.com/lampjaw/mutterblack.discord/plugins/uwutranslatorplugin"
-	"github.com/lampjaw/mutterblack.discord/plugins/weatherplugin"
+	"github.com/lampjaw/mutterblack.discord/plugins"
 )
 
 func init() {
 	token = os.Getenv("TOKEN")
 	clientID = os.Getenv("CLIENT_ID")
 	ownerUserID = os.Getenv("OWNER_USER_ID")
+	panicChannelID = os.Getenv("PANIC_CHANNEL_ID")
 }
 
 var token string
 var clientID string
 var ownerUserID string
+var panicChannelID string
 var buffer = make([][]byte, 0)
 
 func main() {
@@ -34,13 +31,17 @@ func main() {
 	}
 
 	bot := mutterblack.NewBot(token, clientID, ownerUserID)
+	bot.PanicChannelID = panicChannelID
+
+	backend, err := mutterblack.BackendFromEnvironment(bot.Core)
+	if err != nil {
+		fmt.Println("Error opening storage backend:", err)
+		return
+	}
+	bot.Backend = backend
 
 	commandPlugin := mutterblack.NewCommandPlugin()
-	commandPlugin.AddCommand("invite", inviteplugin.InviteCommand, inviteplugin.InviteHelp)
-	commandPlugin.AddCommand("join", inviteplugin.InviteCommand, nil)
-	commandPlugin.AddCommand("stats", statsplugin.StatsCommand, statsplugin.StatsHelp)
-	commandPlugin.AddCommand("info", statsplugin.StatsCommand, nil)
-	commandPlugin.AddCommand("stat", statsplugin.StatsCommand, nil)
+	plugins.AddCommands(commandPlugin)
 	commandPlugin.AddCommand("quit", func(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, args string, parts []string) {
 		if client.IsBotOwner(message) {
 			q <- true
@@ -48,18 +49,15 @@ func main() {
 	}, nil)
 
 	bot.RegisterPlugin(commandPlugin)
-	bot.RegisterPlugin(mutterblack.NewHelpPlugin())
-	bot.RegisterPlugin(weatherplugin.New())
-	bot.RegisterPlugin(planetsidetwoplugin.New())
-	bot.RegisterPlugin(uwutranslatorplugin.New())
+	for _, plugin := range plugins.New() {
+		bot.RegisterPlugin(plugin)
+	}
 
 	bot.Open()
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, os.Kill)
 
-	t := time.Tick(1 * time.Minute)
-
 out:
 	for {
 		select {
@@ -67,10 +65,10 @@ out:
 			break out
 		case <-c:
 			break out
-		case <-t:
-			bot.Save()
 		}
 	}
 
-	bot.Save()
+	if err := bot.Close(); err != nil {
+		fmt.Println("Error closing bot:", err)
+	}
 }
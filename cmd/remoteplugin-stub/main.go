@@ -0,0 +1,115 @@
+// Command remoteplugin-stub is a small remote plugin to try the remote plugin protocol against.
+//
+// Run it and point the bot at it:
+//
+//	go run ./cmd/remoteplugin-stub -addr :8090
+//	REMOTE_PLUGINS=Stub=http://localhost:8090/ mutterblack
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/lampjaw/mutterblack.discord/plugins/remoteplugin"
+)
+
+var description = remoteplugin.Description{
+	Commands: []remoteplugin.Command{
+		{
+			ID:          "stub-roll",
+			Triggers:    []string{"roll"},
+			Description: "Roll a die.",
+			Arguments: []remoteplugin.Argument{
+				{Alias: "sides", Pattern: "[0-9]+", Optional: true, Type: "number", Description: "Number of sides, defaults to 6."},
+			},
+			Examples: []string{"20"},
+		},
+		{
+			ID:          "stub-echo",
+			Triggers:    []string{"echo"},
+			Description: "Repeat a message in an embed.",
+			Arguments: []remoteplugin.Argument{
+				{Alias: "text", Pattern: ".+"},
+			},
+			CooldownSeconds: 5,
+		},
+	},
+}
+
+func main() {
+	addr := flag.String("addr", ":8090", "address to listen on")
+	flag.Parse()
+
+	rand.Seed(time.Now().UnixNano())
+
+	http.HandleFunc("/", handle)
+	log.Printf("Listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	request := remoteplugin.Request{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		respond(w, request.ID, nil, &remoteplugin.Error{Code: remoteplugin.ErrorParse, Message: err.Error()})
+		return
+	}
+
+	switch request.Method {
+	case remoteplugin.MethodDescribe:
+		respond(w, request.ID, description, nil)
+	case remoteplugin.MethodHealth:
+		respond(w, request.ID, remoteplugin.Health{OK: true}, nil)
+	case remoteplugin.MethodCommand:
+		invocation := remoteplugin.Invocation{}
+		if err := json.Unmarshal(request.Params, &invocation); err != nil {
+			respond(w, request.ID, nil, &remoteplugin.Error{Code: remoteplugin.ErrorInvalidParams, Message: err.Error()})
+			return
+		}
+		respond(w, request.ID, command(invocation), nil)
+	default:
+		respond(w, request.ID, nil, &remoteplugin.Error{Code: remoteplugin.ErrorMethodNotFound, Message: "unknown method " + request.Method})
+	}
+}
+
+func command(invocation remoteplugin.Invocation) remoteplugin.Result {
+	switch invocation.Command {
+	case "stub-roll":
+		sides, err := strconv.Atoi(invocation.Arguments["sides"])
+		if err != nil || sides < 1 {
+			sides = 6
+		}
+		return remoteplugin.Result{Replies: []remoteplugin.Reply{
+			{Content: fmt.Sprintf("%s rolled %d (d%d)", invocation.Message.UserName, rand.Intn(sides)+1, sides)},
+		}}
+	case "stub-echo":
+		return remoteplugin.Result{Replies: []remoteplugin.Reply{
+			{Embed: &discordgo.MessageEmbed{
+				Author:      &discordgo.MessageEmbedAuthor{Name: invocation.Message.UserName},
+				Description: invocation.Arguments["text"],
+				Color:       0x070707,
+			}},
+		}}
+	}
+	return remoteplugin.Result{}
+}
+
+func respond(w http.ResponseWriter, id int64, result interface{}, rpcError *remoteplugin.Error) {
+	response := remoteplugin.Response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   rpcError,
+	}
+	if result != nil {
+		response.Result, _ = json.Marshal(result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
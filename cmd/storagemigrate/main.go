@@ -0,0 +1,50 @@
+// Command storagemigrate copies the saved plugin data from one storage backend to another.
+//
+// The bot must be stopped while it runs:
+//
+//	go run ./cmd/storagemigrate -from file -to bolt
+//	go run ./cmd/storagemigrate -from bolt -from-path mutterblack.db -to core
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/lampjaw/mutterblack.discord"
+)
+
+func main() {
+	fromKind := flag.String("from", mutterblack.BackendFile, "backend to copy from: file, bolt or core")
+	fromPath := flag.String("from-path", "", "directory or database of the backend to copy from, empty for its default")
+	toKind := flag.String("to", "", "backend to copy to: file, bolt or core")
+	toPath := flag.String("to-path", "", "directory or database of the backend to copy to, empty for its default")
+	flag.Parse()
+
+	if *toKind == "" {
+		log.Fatalln("No backend to copy to, use -to")
+	}
+
+	core := mutterblack.CoreClientFromEnvironment()
+
+	from, err := mutterblack.NewBackend(*fromKind, *fromPath, core)
+	if err != nil {
+		log.Fatalln("Error opening backend to copy from", err)
+	}
+	defer from.Close()
+
+	to, err := mutterblack.NewBackend(*toKind, *toPath, core)
+	if err != nil {
+		log.Fatalln("Error opening backend to copy to", err)
+	}
+	defer to.Close()
+
+	copied, err := mutterblack.MigrateBackend(from, to)
+	for _, name := range copied {
+		log.Printf("Copied %s", name)
+	}
+	if err != nil {
+		log.Fatalln("Error migrating", err)
+	}
+
+	log.Printf("Copied %d entries from %s to %s", len(copied), *fromKind, *toKind)
+}
@@ -2,6 +2,28 @@ package mutterblack
 
 import (
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ArgumentType describes the kind of value a command argument accepts, it is shown in detailed help.
+type ArgumentType string
+
+const (
+	// ArgumentTypeText is free form text that may contain spaces.
+	ArgumentTypeText ArgumentType = "text"
+	// ArgumentTypeWord is a single word without spaces.
+	ArgumentTypeWord = "word"
+	// ArgumentTypeNumber is a whole number.
+	ArgumentTypeNumber = "number"
+	// ArgumentTypeChannel is a channel mention or name.
+	ArgumentTypeChannel = "channel"
+	// ArgumentTypeRole is a role mention or name.
+	ArgumentTypeRole = "role"
+	// ArgumentTypeKeyword is a fixed word, the alias is shown as-is in usage and the pattern should match it.
+	ArgumentTypeKeyword = "keyword"
 )
 
 type CommandDefinition struct {
@@ -11,22 +33,159 @@ type CommandDefinition struct {
 	Triggers     []string
 	Arguments    []CommandDefinitionArgument
 	Callback     func(bot *Bot, client *Discord, message Message, args map[string]string, trigger string)
+
+	// Examples are argument strings shown after the first trigger in detailed help.
+	Examples []string
+	// Permissions is the set of discordgo permission bits the caller needs in the channel.
+	Permissions int
+	// ModeratorOnly restricts the command to server moderators.
+	ModeratorOnly bool
+	// OwnerOnly restricts the command to the bot owner.
+	OwnerOnly bool
+	// Cooldown is the time a user has to wait between uses of the command.
+	Cooldown time.Duration
 }
 
 type CommandDefinitionArgument struct {
 	Optional bool
 	Pattern  string
 	Alias    string
+
+	// Type is the kind of value the argument accepts, defaults to ArgumentTypeText.
+	Type ArgumentType
+	// Description is shown in detailed help, it may be a catalog key.
+	Description string
 }
 
-func (c *CommandDefinition) Help(client *Discord) string {
-	commandString := fmt.Sprintf("%s%s", client.CommandPrefix(), c.Triggers[0])
+// Usage returns how a command is invoked using prefix, eg. `?w <location>`.
+func (c *CommandDefinition) Usage(prefix string) string {
+	commandString := fmt.Sprintf("%s%s", prefix, c.Triggers[0])
 
-	if len(c.Arguments) > 0 {
-		for _, argument := range c.Arguments {
+	for _, argument := range c.Arguments {
+		if argument.Type == ArgumentTypeKeyword {
+			commandString = fmt.Sprintf("%s %s", commandString, argument.Alias)
+		} else if argument.Optional {
+			commandString = fmt.Sprintf("%s [%s]", commandString, argument.Alias)
+		} else {
 			commandString = fmt.Sprintf("%s <%s>", commandString, argument.Alias)
 		}
 	}
 
-	return fmt.Sprintf("`%s` - %s", commandString, c.Description)
+	return commandString
+}
+
+// Help returns the one line help text for a command using prefix, with the description translated by the localizer.
+func (c *CommandDefinition) Help(prefix string, localizer *Localizer) string {
+	return fmt.Sprintf("`%s` - %s", c.Usage(prefix), localizer.T(c.Description))
+}
+
+// HelpEmbed returns the detailed help for a command, listing its aliases, arguments, examples and restrictions.
+func (c *CommandDefinition) HelpEmbed(prefix string, localizer *Localizer) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       c.Usage(prefix),
+		Description: localizer.T(c.Description),
+		Color:       0x070707,
+	}
+
+	if len(c.Triggers) > 1 {
+		aliases := []string{}
+		for _, trigger := range c.Triggers[1:] {
+			aliases = append(aliases, fmt.Sprintf("`%s%s`", prefix, trigger))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  localizer.N("help.field.aliases", len(aliases)),
+			Value: strings.Join(aliases, ", "),
+		})
+	}
+
+	arguments := []string{}
+	for _, argument := range c.Arguments {
+		if argument.Type == ArgumentTypeKeyword {
+			continue
+		}
+
+		argumentType := argument.Type
+		if argumentType == "" {
+			argumentType = ArgumentTypeText
+		}
+
+		requirement := localizer.T("help.argument.required")
+		if argument.Optional {
+			requirement = localizer.T("help.argument.optional")
+		}
+
+		line := fmt.Sprintf("`%s` (%s, %s)", argument.Alias, localizer.T("help.argument-type."+string(argumentType)), requirement)
+		if argument.Description != "" {
+			line = fmt.Sprintf("%s - %s", line, localizer.T(argument.Description))
+		}
+		arguments = append(arguments, line)
+	}
+	if len(arguments) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  localizer.N("help.field.arguments", len(arguments)),
+			Value: strings.Join(arguments, "\n"),
+		})
+	}
+
+	if len(c.Examples) > 0 {
+		examples := []string{}
+		for _, example := range c.Examples {
+			examples = append(examples, fmt.Sprintf("`%s%s %s`", prefix, c.Triggers[0], example))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  localizer.N("help.field.examples", len(examples)),
+			Value: strings.Join(examples, "\n"),
+		})
+	}
+
+	if restrictions := c.Restrictions(localizer); len(restrictions) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  localizer.T("help.field.permissions"),
+			Value: strings.Join(restrictions, ", "),
+		})
+	}
+
+	if c.Cooldown > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  localizer.T("help.field.cooldown"),
+			Value: c.Cooldown.String(),
+		})
+	}
+
+	return embed
+}
+
+var permissionNames = []struct {
+	permission int
+	key        string
+}{
+	{discordgo.PermissionAdministrator, "permission.administrator"},
+	{discordgo.PermissionManageServer, "permission.manage-server"},
+	{discordgo.PermissionManageChannels, "permission.manage-channels"},
+	{discordgo.PermissionManageRoles, "permission.manage-roles"},
+	{discordgo.PermissionManageMessages, "permission.manage-messages"},
+	{discordgo.PermissionKickMembers, "permission.kick-members"},
+	{discordgo.PermissionBanMembers, "permission.ban-members"},
+	{discordgo.PermissionEmbedLinks, "permission.embed-links"},
+	{discordgo.PermissionAttachFiles, "permission.attach-files"},
+	{discordgo.PermissionReadMessageHistory, "permission.read-message-history"},
+}
+
+// Restrictions returns the translated names of the permissions a command requires.
+func (c *CommandDefinition) Restrictions(localizer *Localizer) []string {
+	restrictions := []string{}
+
+	if c.OwnerOnly {
+		restrictions = append(restrictions, localizer.T("permission.bot-owner"))
+	}
+	if c.ModeratorOnly {
+		restrictions = append(restrictions, localizer.T("permission.moderator"))
+	}
+	for _, permission := range permissionNames {
+		if c.Permissions&permission.permission == permission.permission {
+			restrictions = append(restrictions, localizer.T(permission.key))
+		}
+	}
+
+	return restrictions
 }
@@ -0,0 +1,184 @@
+package mutterblack
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// commandDenied returns why the author of a message may not run a command, or an empty string if they may.
+// A nil commandDefinition only checks the guild wide restrictions, for plugins that match their own commands.
+func (b *Bot) commandDenied(plugin Plugin, commandDefinition *CommandDefinition, message Message) string {
+	l := b.Localizer(message)
+	client := b.Client
+
+	if commandDefinition != nil {
+		if commandDefinition.OwnerOnly && !client.IsBotOwner(message) {
+			return l.T("error.owner-only")
+		}
+
+		if commandDefinition.ModeratorOnly && (client.IsPrivate(message) || !client.IsModerator(message)) {
+			return l.T("error.moderator-only")
+		}
+
+		if commandDefinition.Permissions != 0 && !client.IsBotOwner(message) {
+			p, err := client.UserChannelPermissions(message.UserID(), message.Channel())
+			if err != nil || p&commandDefinition.Permissions != commandDefinition.Permissions {
+				return l.T("error.missing-permissions")
+			}
+		}
+	}
+
+	// Configuration commands ignore guild restrictions so moderators can't lock themselves out.
+	if plugin != nil && plugin.Name() == configurePluginName {
+		return ""
+	}
+
+	config := client.messageGuildConfiguration(message)
+	if config == nil {
+		return ""
+	}
+
+	var commandConfig *GuildCommandConfiguration
+	if commandDefinition != nil {
+		commandConfig = config.CommandConfigurations[commandDefinition.CommandID]
+	}
+
+	if commandConfig != nil && !commandConfig.Enabled {
+		return l.T("error.command-disabled")
+	}
+
+	// Moderators are not bound by channel and role restrictions.
+	if client.IsModerator(message) {
+		return ""
+	}
+
+	roles := client.UserRoles(client.ChannelGuildID(message.Channel()), message.UserID())
+
+	if !allows(config.AllowedChannels, message.Channel()) {
+		return l.T("error.channel-restricted", channelMentions(config.AllowedChannels))
+	}
+	if !allowsAny(config.AllowedRoles, roles) {
+		return l.T("error.role-restricted")
+	}
+
+	if commandConfig != nil {
+		if !allows(commandConfig.AllowedChannels, message.Channel()) {
+			return l.T("error.channel-restricted", channelMentions(commandConfig.AllowedChannels))
+		}
+		if !allowsAny(commandConfig.AllowedRoles, roles) {
+			return l.T("error.role-restricted")
+		}
+	}
+
+	return ""
+}
+
+// protectedPlugins can't be disabled in a guild, so moderators can always get to help and undo their configuration.
+var protectedPlugins = map[string]bool{
+	helpPluginName:        true,
+	localePluginName:      true,
+	maintenancePluginName: true,
+	configurePluginName:   true,
+	dataPluginName:        true,
+}
+
+// pluginEnabled returns false if a guild disabled a plugin, or only allows other plugins.
+func (c *GuildConfiguration) pluginEnabled(name string) bool {
+	if protectedPlugins[name] {
+		return true
+	}
+
+	for _, disabled := range c.DisabledPlugins {
+		if strings.EqualFold(disabled, name) {
+			return false
+		}
+	}
+
+	if len(c.AllowedPlugins) == 0 {
+		return true
+	}
+	for _, allowed := range c.AllowedPlugins {
+		if strings.EqualFold(allowed, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginDisabled returns true if a guild has disabled a plugin, it then gets no messages, commands or events from that guild.
+// A nil config, for private messages or a configuration that couldn't be loaded, disables nothing.
+func (b *Bot) pluginDisabled(plugin Plugin, config *GuildConfiguration) bool {
+	return config != nil && !config.pluginEnabled(plugin.Name())
+}
+
+// cooldownSweepInterval is how often cooldowns that have run out are dropped.
+const cooldownSweepInterval = time.Minute
+
+// commandCooldown starts the cooldown of a command for the author of a message, or returns a message if it is still running.
+func (b *Bot) commandCooldown(commandDefinition *CommandDefinition, message Message) string {
+	if commandDefinition.Cooldown <= 0 {
+		return ""
+	}
+
+	key := message.UserID() + "|" + commandDefinition.CommandID
+	now := time.Now()
+
+	b.cooldownsLock.Lock()
+	defer b.cooldownsLock.Unlock()
+
+	if now.Sub(b.cooldownsSwept) >= cooldownSweepInterval {
+		b.sweepCooldowns(now)
+	}
+
+	if until, ok := b.cooldowns[key]; ok && now.Before(until) {
+		return b.Localizer(message).T("error.cooldown", until.Sub(now).Round(time.Second))
+	}
+	b.cooldowns[key] = now.Add(commandDefinition.Cooldown)
+
+	return ""
+}
+
+// sweepCooldowns drops the cooldowns that have run out, the caller holds cooldownsLock.
+func (b *Bot) sweepCooldowns(now time.Time) {
+	for key, until := range b.cooldowns {
+		if !now.Before(until) {
+			delete(b.cooldowns, key)
+		}
+	}
+	b.cooldownsSwept = now
+}
+
+// allows returns true if allowed is empty, meaning unrestricted, or contains value.
+func allows(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == value {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsAny returns true if allowed is empty, meaning unrestricted, or contains any of values.
+func allowsAny(allowed []string, values []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if allows(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func channelMentions(channelIDs []string) string {
+	mentions := []string{}
+	for _, channelID := range channelIDs {
+		mentions = append(mentions, fmt.Sprintf("<#%s>", channelID))
+	}
+	return strings.Join(mentions, ", ")
+}
@@ -14,17 +14,22 @@ type CommandHelpFunc func(bot *Bot, client *Discord, message Message) (string, s
 type CommandMessageFunc func(bot *Bot, client *Discord, message Message, args string, parts []string)
 
 // NewCommandHelp creates a new Command Help function.
+// The help text may be a catalog key, it is translated for the requesting user.
 func NewCommandHelp(args, help string) CommandHelpFunc {
 	return func(bot *Bot, client *Discord, message Message) (string, string) {
-		return args, help
+		return args, bot.Localizer(message).T(help)
 	}
 }
 
 // MatchesCommandString returns true if a message matches a command.
-// Commands will be matched ignoring case with a prefix if they are not private messages.
+// Commands will be matched ignoring case with the default prefix if they are not private messages, use MatchesCommand to match with the prefix of the guild.
 func MatchesCommandString(client *Discord, commandString string, private bool, message string) bool {
+	return matchesCommandString(client.CommandPrefix(), commandString, private, message)
+}
+
+func matchesCommandString(prefix string, commandString string, private bool, message string) bool {
 	lowerMessage := strings.ToLower(strings.TrimSpace(message))
-	lowerPrefix := strings.ToLower(client.CommandPrefix())
+	lowerPrefix := strings.ToLower(prefix)
 
 	if strings.HasPrefix(lowerMessage, lowerPrefix) {
 		lowerMessage = lowerMessage[len(lowerPrefix):]
@@ -44,15 +49,20 @@ func MatchesCommand(client *Discord, commandString string, message Message) bool
 	if message.Type() == MessageTypeDelete {
 		return false
 	}
-	return MatchesCommandString(client, commandString, client.IsPrivate(message), message.Message())
+	return matchesCommandString(client.MessageCommandPrefix(message), commandString, client.IsPrivate(message), message.Message())
 }
 
 // ParseCommandString will strip all prefixes from a message string, and return that string, and a space separated tokenized version of that string.
+// Only the default prefix is stripped, use ParseCommand to strip the prefix of the guild.
 func ParseCommandString(client *Discord, message string) (string, []string) {
+	return parseCommandString(client.CommandPrefix(), message)
+}
+
+func parseCommandString(prefix string, message string) (string, []string) {
 	message = strings.TrimSpace(message)
 
 	lowerMessage := strings.ToLower(message)
-	lowerPrefix := strings.ToLower(client.CommandPrefix())
+	lowerPrefix := strings.ToLower(prefix)
 
 	if strings.HasPrefix(lowerMessage, lowerPrefix) {
 		message = message[len(lowerPrefix):]
@@ -68,18 +78,28 @@ func ParseCommandString(client *Discord, message string) (string, []string) {
 
 // ParseCommand parses a message.
 func ParseCommand(client *Discord, message Message) (string, []string) {
-	return ParseCommandString(client, message.Message())
+	return parseCommandString(client.MessageCommandPrefix(message), message.Message())
 }
 
 // CommandHelp is a helper message that creates help text for a command.
 // eg. CommandHelp(service, "foo", "<bar>", "Foo bar baz") will return:
 //     !foo <bar> - Foo bar baz
 // The string is automatatically styled in Discord.
+// The default prefix is used, use ChannelCommandHelp to show the prefix of the guild.
 func CommandHelp(client *Discord, command, arguments, help string) []string {
+	return commandHelp(client.CommandPrefix(), command, arguments, help)
+}
+
+// ChannelCommandHelp creates help text for a command like CommandHelp, with the prefix configured for the guild a channel belongs to.
+func ChannelCommandHelp(client *Discord, channelID string, command, arguments, help string) []string {
+	return commandHelp(client.ChannelCommandPrefix(channelID), command, arguments, help)
+}
+
+func commandHelp(prefix string, command, arguments, help string) []string {
 	if arguments != "" {
-		return []string{fmt.Sprintf("`%s%s %s` - %s", client.CommandPrefix(), command, arguments, help)}
+		return []string{fmt.Sprintf("`%s%s %s` - %s", prefix, command, arguments, help)}
 	}
-	return []string{fmt.Sprintf("`%s%s` - %s", client.CommandPrefix(), command, help)}
+	return []string{fmt.Sprintf("`%s%s` - %s", prefix, command, help)}
 }
 
 type command struct {
@@ -92,37 +112,23 @@ type CommandPlugin struct {
 	commands map[string]*command
 }
 
-func (p *CommandPlugin) Commands() []CommandDefinition {
-	return nil
-}
-
 // Name returns the name of the plugin.
 func (p *CommandPlugin) Name() string {
 	return "Command"
 }
 
-// Load will load plugin state from a byte array.
-func (p *CommandPlugin) Load(bot *Bot, client *Discord, data []byte) error {
-	// TODO: Add a generic data store backed by json.
-	return nil
-}
-
-// Save will save plugin state to a byte array.
-func (p *CommandPlugin) Save() ([]byte, error) {
-	// TODO: Add a generic data store backed by json.
-	return nil, nil
-}
-
 // Help returns a list of help strings that are printed when the user requests them.
 func (p *CommandPlugin) Help(bot *Bot, client *Discord, message Message, detailed bool) []string {
 	if detailed {
 		return nil
 	}
+	prefix := client.MessageCommandPrefix(message)
+
 	help := []string{}
 	for commandString, command := range p.commands {
 		if command.help != nil {
 			arguments, h := command.help(bot, client, message)
-			help = append(help, CommandHelp(client, commandString, arguments, h)...)
+			help = append(help, commandHelp(prefix, commandString, arguments, h)...)
 		}
 	}
 	return help
@@ -131,12 +137,18 @@ func (p *CommandPlugin) Help(bot *Bot, client *Discord, message Message, detaile
 // Message handler.
 // Iterates over the registered commands and executes them if the message matches.
 func (p *CommandPlugin) Message(bot *Bot, client *Discord, message Message) {
-	defer MessageRecover()
 	if !client.IsMe(message) {
 		for commandString, command := range p.commands {
 			if MatchesCommand(client, commandString, message) {
+				if reason := bot.commandDenied(p, nil, message); reason != "" {
+					client.SendMessage(message.Channel(), reason)
+					return
+				}
+
 				args, parts := ParseCommand(client, message)
-				command.message(bot, client, message, args, parts)
+				bot.protect(p, commandString, message.Message(), func() {
+					command.message(bot, client, message, args, parts)
+				})
 				return
 			}
 		}
@@ -151,11 +163,6 @@ func (p *CommandPlugin) AddCommand(commandString string, message CommandMessageF
 	}
 }
 
-// Stats will return the stats for a plugin.
-func (p *CommandPlugin) Stats(bot *Bot, client *Discord, message Message) []string {
-	return nil
-}
-
 // NewCommandPlugin will create a new command plugin.
 func NewCommandPlugin() *CommandPlugin {
 	return &CommandPlugin{make(map[string]*command)}
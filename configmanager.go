@@ -3,15 +3,27 @@ package mutterblack
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
+const guildConfigurationCacheDuration = 5 * time.Minute
+
+// guildConfigurationRetryDuration is how long a configuration that couldn't be loaded is not asked for again.
+const guildConfigurationRetryDuration = 30 * time.Second
+
 type GuildConfiguration struct {
 	Platform              string
 	GuildID               string `json:"key"`
 	Prefix                string
+	Locale                string
 	AllowedChannels       []string
 	AllowedRoles          []string
 	CommandConfigurations map[string]*GuildCommandConfiguration
+	Help                  *HelpConfiguration
+	ChannelHelp           map[string]*HelpConfiguration
+	AllowedPlugins        []string
+	DisabledPlugins       []string
+	PluginSettings        map[string]map[string]string
 }
 
 type GuildCommandConfiguration struct {
@@ -21,6 +33,26 @@ type GuildCommandConfiguration struct {
 	Enabled         bool
 }
 
+// HelpMode is how help is delivered when it is requested in a guild channel.
+type HelpMode string
+
+const (
+	// HelpModePublic posts help in the channel it was requested in.
+	HelpModePublic HelpMode = "public"
+	// HelpModePrivate sends help through a private message.
+	HelpModePrivate = "private"
+	// HelpModeEphemeral posts help in the channel and deletes it after DeleteAfter seconds.
+	HelpModeEphemeral = "ephemeral"
+)
+
+// defaultHelpDeleteAfter is used for ephemeral help when no delay was configured.
+const defaultHelpDeleteAfter = 60
+
+type HelpConfiguration struct {
+	Mode        HelpMode
+	DeleteAfter int
+}
+
 func newGuildConfiguration(guildID string) *GuildConfiguration {
 	return &GuildConfiguration{
 		Platform:              "Discord",
@@ -29,6 +61,7 @@ func newGuildConfiguration(guildID string) *GuildConfiguration {
 		AllowedChannels:       make([]string, 0),
 		AllowedRoles:          make([]string, 0),
 		CommandConfigurations: make(map[string]*GuildCommandConfiguration),
+		ChannelHelp:           make(map[string]*HelpConfiguration),
 	}
 }
 
@@ -41,9 +74,149 @@ func newGuildCommandConfiguration(commandID string) *GuildCommandConfiguration {
 	}
 }
 
-func findGuildConfiguration(guildID string) *GuildConfiguration {
+// commandConfiguration returns the configuration for a command, creating it if the guild has none yet.
+func (c *GuildConfiguration) commandConfiguration(commandID string) *GuildCommandConfiguration {
+	if c.CommandConfigurations == nil {
+		c.CommandConfigurations = make(map[string]*GuildCommandConfiguration)
+	}
+
+	commandConfig, ok := c.CommandConfigurations[commandID]
+	if !ok {
+		commandConfig = newGuildCommandConfiguration(commandID)
+		c.CommandConfigurations[commandID] = commandConfig
+	}
+	return commandConfig
+}
+
+// helpConfiguration returns how help is delivered in a channel, channel settings win over the guild wide one.
+func (c *GuildConfiguration) helpConfiguration(channelID string) *HelpConfiguration {
+	if help, ok := c.ChannelHelp[channelID]; ok && help != nil {
+		return help
+	}
+	if c.Help != nil {
+		return c.Help
+	}
+	return &HelpConfiguration{Mode: HelpModePublic}
+}
+
+// setChannelHelp sets how help is delivered in a channel, a nil help makes the channel use the guild wide setting.
+func (c *GuildConfiguration) setChannelHelp(channelID string, help *HelpConfiguration) {
+	if c.ChannelHelp == nil {
+		c.ChannelHelp = make(map[string]*HelpConfiguration)
+	}
+
+	if help == nil {
+		delete(c.ChannelHelp, channelID)
+	} else {
+		c.ChannelHelp[channelID] = help
+	}
+}
+
+// clone returns a deep copy of a configuration.
+func (c *GuildConfiguration) clone() *GuildConfiguration {
+	config := *c
+	config.AllowedChannels = cloneStrings(c.AllowedChannels)
+	config.AllowedRoles = cloneStrings(c.AllowedRoles)
+	config.AllowedPlugins = cloneStrings(c.AllowedPlugins)
+	config.DisabledPlugins = cloneStrings(c.DisabledPlugins)
+
+	if c.CommandConfigurations != nil {
+		config.CommandConfigurations = make(map[string]*GuildCommandConfiguration, len(c.CommandConfigurations))
+		for commandID, commandConfig := range c.CommandConfigurations {
+			if commandConfig == nil {
+				continue
+			}
+			copied := *commandConfig
+			copied.AllowedChannels = cloneStrings(commandConfig.AllowedChannels)
+			copied.AllowedRoles = cloneStrings(commandConfig.AllowedRoles)
+			config.CommandConfigurations[commandID] = &copied
+		}
+	}
+
+	if c.Help != nil {
+		help := *c.Help
+		config.Help = &help
+	}
+	if c.ChannelHelp != nil {
+		config.ChannelHelp = make(map[string]*HelpConfiguration, len(c.ChannelHelp))
+		for channelID, channelHelp := range c.ChannelHelp {
+			if channelHelp == nil {
+				continue
+			}
+			help := *channelHelp
+			config.ChannelHelp[channelID] = &help
+		}
+	}
+
+	if c.PluginSettings != nil {
+		config.PluginSettings = make(map[string]map[string]string, len(c.PluginSettings))
+		for pluginName, settings := range c.PluginSettings {
+			copied := make(map[string]string, len(settings))
+			for key, value := range settings {
+				copied[key] = value
+			}
+			config.PluginSettings[pluginName] = copied
+		}
+	}
+
+	return &config
+}
+
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(values)), values...)
+}
+
+type cachedGuildConfiguration struct {
+	configuration *GuildConfiguration
+	expires       time.Time
+}
+
+// cacheGuildConfiguration caches the configuration of a guild, a nil configuration is one that couldn't be loaded.
+func (c *CoreClient) cacheGuildConfiguration(guildID string, configuration *GuildConfiguration) {
+	duration := guildConfigurationCacheDuration
+	if configuration == nil {
+		duration = guildConfigurationRetryDuration
+	}
+
+	c.guildConfigurationsLock.Lock()
+	c.guildConfigurations[guildID] = cachedGuildConfiguration{
+		configuration: configuration,
+		expires:       time.Now().Add(duration),
+	}
+	c.guildConfigurationsLock.Unlock()
+}
+
+func (c *CoreClient) findGuildConfiguration(guildID string) *GuildConfiguration {
+	c.guildConfigurationsLock.RLock()
+	cached, ok := c.guildConfigurations[guildID]
+	c.guildConfigurationsLock.RUnlock()
+
+	if ok && time.Now().Before(cached.expires) {
+		return cached.configuration
+	}
+
+	configuration := c.fetchGuildConfiguration(guildID)
+	c.cacheGuildConfiguration(guildID, configuration)
+
+	return configuration
+}
+
+// editGuildConfiguration returns a copy of the configuration of a guild to change and pass to saveGuildConfiguration.
+// The cached configuration is read by every message and is never changed in place, it is replaced once the copy is saved.
+func (c *CoreClient) editGuildConfiguration(guildID string) *GuildConfiguration {
+	configuration := c.findGuildConfiguration(guildID)
+	if configuration == nil {
+		return nil
+	}
+	return configuration.clone()
+}
+
+func (c *CoreClient) fetchGuildConfiguration(guildID string) *GuildConfiguration {
 	var path = fmt.Sprintf("configuration/discord/%s", guildID)
-	resp, err := SendCoreGet(path)
+	resp, err := c.Get(path)
 	if err != nil {
 		return nil
 	}
@@ -52,17 +225,17 @@ func findGuildConfiguration(guildID string) *GuildConfiguration {
 	json.Unmarshal(resp, &configuration)
 
 	if configuration == nil {
-		return createGuildConfiguration(guildID)
+		return c.createGuildConfiguration(guildID)
 	}
 
 	return configuration
 }
 
-func createGuildConfiguration(guildID string) *GuildConfiguration {
+func (c *CoreClient) createGuildConfiguration(guildID string) *GuildConfiguration {
 	config := newGuildConfiguration(guildID)
 
 	var path = fmt.Sprintf("configuration/discord/%s", guildID)
-	resp, err := SendCorePost(path, config)
+	resp, err := c.Post(path, config)
 	if err != nil {
 		return nil
 	}
@@ -72,3 +245,28 @@ func createGuildConfiguration(guildID string) *GuildConfiguration {
 
 	return configuration
 }
+
+func (c *CoreClient) saveGuildConfiguration(config *GuildConfiguration) error {
+	var path = fmt.Sprintf("configuration/discord/%s", config.GuildID)
+	if _, err := c.Post(path, config); err != nil {
+		return err
+	}
+
+	c.cacheGuildConfiguration(config.GuildID, config)
+
+	return nil
+}
+
+// deleteGuildConfiguration removes the configuration of a guild, the defaults are used from then on.
+func (c *CoreClient) deleteGuildConfiguration(guildID string) error {
+	var path = fmt.Sprintf("configuration/discord/%s", guildID)
+	if _, err := c.Delete(path); err != nil {
+		return err
+	}
+
+	c.guildConfigurationsLock.Lock()
+	delete(c.guildConfigurations, guildID)
+	c.guildConfigurationsLock.Unlock()
+
+	return nil
+}
@@ -0,0 +1,736 @@
+package mutterblack
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const configurePluginName = "Configure"
+
+type configurePlugin struct{}
+
+// configureAction describes one of the configure commands, the plugin builds its command definitions from these.
+type configureAction struct {
+	commandID   string
+	keyword     string
+	perCommand  bool
+	perPlugin   bool
+	arguments   []CommandDefinitionArgument
+	description string
+	example     string
+	callback    func(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string)
+}
+
+func (p *configurePlugin) Name() string {
+	return configurePluginName
+}
+
+func (p *configurePlugin) actions() []configureAction {
+	channelArgument := CommandDefinitionArgument{
+		Pattern:     `\S+`,
+		Alias:       "channel",
+		Type:        ArgumentTypeChannel,
+		Description: "configure.argument.channel",
+	}
+	roleArgument := CommandDefinitionArgument{
+		Pattern:     ".+",
+		Alias:       "role",
+		Type:        ArgumentTypeRole,
+		Description: "configure.argument.role",
+	}
+	pluginArgument := CommandDefinitionArgument{
+		Pattern:     `\S+`,
+		Alias:       "plugin",
+		Type:        ArgumentTypeWord,
+		Description: "configure.argument.plugin",
+	}
+	helpModeArgument := CommandDefinitionArgument{
+		Pattern:     "(?i:public|private|ephemeral)",
+		Alias:       "mode",
+		Type:        ArgumentTypeWord,
+		Description: "configure.argument.help-mode",
+	}
+	settingArgument := CommandDefinitionArgument{
+		Pattern:     `\S+`,
+		Alias:       "key",
+		Type:        ArgumentTypeWord,
+		Description: "configure.argument.key",
+	}
+	secondsArgument := CommandDefinitionArgument{
+		Optional:    true,
+		Pattern:     `\d{1,4}`,
+		Alias:       "seconds",
+		Type:        ArgumentTypeNumber,
+		Description: "configure.argument.seconds",
+	}
+
+	return []configureAction{
+		{
+			commandID: "configure-prefix",
+			keyword:   "prefix",
+			arguments: []CommandDefinitionArgument{
+				CommandDefinitionArgument{
+					Pattern:     `\S{1,5}`,
+					Alias:       "commandPrefix",
+					Type:        ArgumentTypeWord,
+					Description: "configure.argument.prefix",
+				},
+			},
+			description: "configure.command.prefix",
+			example:     "prefix !",
+			callback:    p.setPrefix,
+		},
+		{commandID: "configure-channel-set", keyword: "setChannel", arguments: []CommandDefinitionArgument{channelArgument}, description: "configure.command.channel-set", example: "setChannel #bot", callback: p.updateChannels(true)},
+		{commandID: "configure-channel-remove", keyword: "removeChannel", arguments: []CommandDefinitionArgument{channelArgument}, description: "configure.command.channel-remove", example: "removeChannel #bot", callback: p.updateChannels(false)},
+		{commandID: "configure-channel-list", keyword: "listChannels", description: "configure.command.channel-list", callback: p.listChannels},
+		{commandID: "configure-role-set", keyword: "setRole", arguments: []CommandDefinitionArgument{roleArgument}, description: "configure.command.role-set", example: "setRole @Members", callback: p.updateRoles(true)},
+		{commandID: "configure-role-remove", keyword: "removeRole", arguments: []CommandDefinitionArgument{roleArgument}, description: "configure.command.role-remove", example: "removeRole @Members", callback: p.updateRoles(false)},
+		{commandID: "configure-role-list", keyword: "listRoles", description: "configure.command.role-list", callback: p.listRoles},
+		{commandID: "configure-help", keyword: "setHelp", arguments: []CommandDefinitionArgument{helpModeArgument, secondsArgument}, description: "configure.command.help", example: "setHelp ephemeral 30", callback: p.setHelp},
+		{commandID: "configure-channel-help", keyword: "setChannelHelp", arguments: []CommandDefinitionArgument{channelArgument, helpModeArgument, secondsArgument}, description: "configure.command.channel-help", example: "setChannelHelp #general private", callback: p.setHelp},
+		{commandID: "configure-channel-help-remove", keyword: "removeChannelHelp", arguments: []CommandDefinitionArgument{channelArgument}, description: "configure.command.channel-help-remove", example: "removeChannelHelp #general", callback: p.removeChannelHelp},
+		{commandID: "configure-help-show", keyword: "showHelp", description: "configure.command.help-show", callback: p.showHelp},
+		{commandID: "configure-plugin-enable", keyword: "enablePlugin", arguments: []CommandDefinitionArgument{pluginArgument}, description: "configure.command.plugin-enable", example: "enablePlugin uwuTranslator", callback: p.setPluginEnabled(true)},
+		{commandID: "configure-plugin-disable", keyword: "disablePlugin", arguments: []CommandDefinitionArgument{pluginArgument}, description: "configure.command.plugin-disable", example: "disablePlugin uwuTranslator", callback: p.setPluginEnabled(false)},
+		{
+			commandID: "configure-plugin-only",
+			keyword:   "onlyPlugins",
+			arguments: []CommandDefinitionArgument{
+				CommandDefinitionArgument{
+					Pattern:     ".+",
+					Alias:       "plugins",
+					Type:        ArgumentTypeText,
+					Description: "configure.argument.plugins",
+				},
+			},
+			description: "configure.command.plugin-only",
+			example:     "onlyPlugins Weather PS2Stats",
+			callback:    p.setAllowedPlugins,
+		},
+		{commandID: "configure-plugin-list", keyword: "listPlugins", description: "configure.command.plugin-list", callback: p.listPlugins},
+		{
+			commandID: "configure-setting-set",
+			keyword:   "set",
+			perPlugin: true,
+			arguments: []CommandDefinitionArgument{
+				settingArgument,
+				CommandDefinitionArgument{
+					Pattern:     ".+",
+					Alias:       "value",
+					Type:        ArgumentTypeText,
+					Description: "configure.argument.value",
+				},
+			},
+			description: "configure.command.setting-set",
+			example:     "Weather set units metric",
+			callback:    p.setSetting,
+		},
+		{commandID: "configure-setting-reset", keyword: "reset", perPlugin: true, arguments: []CommandDefinitionArgument{settingArgument}, description: "configure.command.setting-reset", example: "Weather reset units", callback: p.resetSetting},
+		{commandID: "configure-setting-show", keyword: "show", perPlugin: true, description: "configure.command.setting-show", example: "Weather show", callback: p.showSettings},
+		{commandID: "configure-command-enable", keyword: "enable", perCommand: true, description: "configure.command.command-enable", example: "ps2c enable", callback: p.setEnabled(true)},
+		{commandID: "configure-command-disable", keyword: "disable", perCommand: true, description: "configure.command.command-disable", example: "ps2c disable", callback: p.setEnabled(false)},
+		{commandID: "configure-command-channel-set", keyword: "setChannel", perCommand: true, arguments: []CommandDefinitionArgument{channelArgument}, description: "configure.command.command-channel-set", example: "w setChannel #weather", callback: p.updateChannels(true)},
+		{commandID: "configure-command-channel-remove", keyword: "removeChannel", perCommand: true, arguments: []CommandDefinitionArgument{channelArgument}, description: "configure.command.command-channel-remove", example: "w removeChannel #weather", callback: p.updateChannels(false)},
+		{commandID: "configure-command-channel-list", keyword: "listChannels", perCommand: true, description: "configure.command.command-channel-list", example: "w listChannels", callback: p.listChannels},
+		{commandID: "configure-command-role-set", keyword: "setRole", perCommand: true, arguments: []CommandDefinitionArgument{roleArgument}, description: "configure.command.command-role-set", example: "ps2o setRole @Outfit", callback: p.updateRoles(true)},
+		{commandID: "configure-command-role-remove", keyword: "removeRole", perCommand: true, arguments: []CommandDefinitionArgument{roleArgument}, description: "configure.command.command-role-remove", example: "ps2o removeRole @Outfit", callback: p.updateRoles(false)},
+		{commandID: "configure-command-role-list", keyword: "listRoles", perCommand: true, description: "configure.command.command-role-list", example: "ps2o listRoles", callback: p.listRoles},
+	}
+}
+
+func (p *configurePlugin) Commands() []CommandDefinition {
+	commandArgument := CommandDefinitionArgument{
+		Pattern:     `\S+`,
+		Alias:       "command",
+		Type:        ArgumentTypeWord,
+		Description: "configure.argument.command",
+	}
+
+	settingsPluginArgument := CommandDefinitionArgument{
+		Pattern:     `\S+`,
+		Alias:       "plugin",
+		Type:        ArgumentTypeWord,
+		Description: "configure.argument.settings-plugin",
+	}
+
+	definitions := []CommandDefinition{}
+
+	for _, action := range p.actions() {
+		arguments := []CommandDefinitionArgument{}
+		if action.perCommand {
+			arguments = append(arguments, commandArgument)
+		}
+		if action.perPlugin {
+			arguments = append(arguments, settingsPluginArgument)
+		}
+		arguments = append(arguments, CommandDefinitionArgument{
+			Pattern: fmt.Sprintf("(?i:%s)", action.keyword),
+			Alias:   action.keyword,
+			Type:    ArgumentTypeKeyword,
+		})
+		arguments = append(arguments, action.arguments...)
+
+		examples := []string{}
+		if action.example != "" {
+			examples = append(examples, action.example)
+		}
+
+		definitions = append(definitions, CommandDefinition{
+			CommandGroup:  p.Name(),
+			CommandID:     action.commandID,
+			Triggers:      []string{"configure"},
+			Arguments:     arguments,
+			Examples:      examples,
+			ModeratorOnly: true,
+			Description:   action.description,
+			Callback:      p.run(action),
+		})
+	}
+
+	return definitions
+}
+
+// run wraps a configure action, resolving the command or plugin it applies to before calling it.
+func (p *configurePlugin) run(action configureAction) func(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	return func(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+		var commandIDs []string
+
+		if action.perCommand {
+			l := bot.Localizer(message)
+
+			commandIDs = p.resolveCommandIDs(bot, client, message, args["command"])
+			if len(commandIDs) == 0 {
+				client.SendMessage(message.Channel(), l.T("configure.unknown-command", args["command"]))
+				return
+			}
+		}
+
+		if action.perPlugin {
+			l := bot.Localizer(message)
+
+			name, ok := p.resolvePlugin(bot, args["plugin"])
+			if !ok {
+				client.SendMessage(message.Channel(), l.T("configure.unknown-plugin", args["plugin"]))
+				return
+			}
+			if len(PluginSettings(bot.Plugins[name])) == 0 {
+				client.SendMessage(message.Channel(), l.T("configure.no-settings", name))
+				return
+			}
+			args["plugin"] = name
+		}
+
+		action.callback(bot, client, message, args, commandIDs)
+	}
+}
+
+// resolveCommandIDs finds the command IDs a moderator referred to, by command ID or by trigger.
+func (p *configurePlugin) resolveCommandIDs(bot *Bot, client *Discord, message Message, command string) []string {
+	command = strings.TrimPrefix(strings.ToLower(command), strings.ToLower(client.MessageCommandPrefix(message)))
+
+	commandIDs := []string{}
+	seen := map[string]bool{}
+
+	for _, plugin := range bot.Plugins {
+		if plugin == Plugin(p) {
+			continue
+		}
+
+		for _, commandDefinition := range PluginCommands(plugin) {
+			matches := strings.ToLower(commandDefinition.CommandID) == command
+			for _, trigger := range commandDefinition.Triggers {
+				if strings.ToLower(trigger) == command {
+					matches = true
+				}
+			}
+
+			if matches && !seen[commandDefinition.CommandID] {
+				seen[commandDefinition.CommandID] = true
+				commandIDs = append(commandIDs, commandDefinition.CommandID)
+			}
+		}
+	}
+
+	sort.Strings(commandIDs)
+	return commandIDs
+}
+
+// guildConfiguration returns a copy of the configuration of the guild a message was sent in to change, replying with an error if it can't be loaded.
+func (p *configurePlugin) guildConfiguration(bot *Bot, client *Discord, message Message) *GuildConfiguration {
+	config := bot.Core.editGuildConfiguration(client.ChannelGuildID(message.Channel()))
+	if config == nil {
+		client.SendMessage(message.Channel(), bot.Localizer(message).T(InterProcessCommunicationFailure))
+	}
+	return config
+}
+
+func (p *configurePlugin) save(bot *Bot, client *Discord, message Message, config *GuildConfiguration, reply string) {
+	l := bot.Localizer(message)
+
+	if err := bot.Core.saveGuildConfiguration(config); err != nil {
+		client.SendMessage(message.Channel(), l.T(err.Error()))
+		return
+	}
+
+	client.SendMessage(message.Channel(), reply)
+}
+
+// lists returns the guild wide list, or the list of every command in commandIDs, selected by field.
+func (p *configurePlugin) lists(config *GuildConfiguration, commandIDs []string, field func(channels *[]string, roles *[]string) *[]string) []*[]string {
+	if commandIDs == nil {
+		return []*[]string{field(&config.AllowedChannels, &config.AllowedRoles)}
+	}
+
+	lists := []*[]string{}
+	for _, commandID := range commandIDs {
+		commandConfig := config.commandConfiguration(commandID)
+		lists = append(lists, field(&commandConfig.AllowedChannels, &commandConfig.AllowedRoles))
+	}
+	return lists
+}
+
+func channelsField(channels *[]string, roles *[]string) *[]string {
+	return channels
+}
+
+func rolesField(channels *[]string, roles *[]string) *[]string {
+	return roles
+}
+
+func (p *configurePlugin) setPrefix(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	config.Prefix = args["commandPrefix"]
+
+	p.save(bot, client, message, config, bot.Localizer(message).T("configure.prefix-set", config.Prefix))
+}
+
+func (p *configurePlugin) updateChannels(add bool) func(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	return func(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+		l := bot.Localizer(message)
+
+		config := p.guildConfiguration(bot, client, message)
+		if config == nil {
+			return
+		}
+
+		channel := client.FindGuildChannel(config.GuildID, trimMention(args["channel"], "<#"))
+		if channel == nil {
+			client.SendMessage(message.Channel(), l.T("configure.unknown-channel", args["channel"]))
+			return
+		}
+
+		for _, list := range p.lists(config, commandIDs, channelsField) {
+			*list = updateList(*list, channel.ID, add)
+		}
+
+		key := "configure.channel-removed"
+		if add {
+			key = "configure.channel-added"
+		}
+		p.save(bot, client, message, config, l.T(key, channel.ID, describeTarget(l, commandIDs)))
+	}
+}
+
+func (p *configurePlugin) updateRoles(add bool) func(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	return func(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+		l := bot.Localizer(message)
+
+		config := p.guildConfiguration(bot, client, message)
+		if config == nil {
+			return
+		}
+
+		role := client.FindGuildRole(config.GuildID, trimMention(args["role"], "<@&"))
+		if role == nil {
+			client.SendMessage(message.Channel(), l.T("configure.unknown-role", args["role"]))
+			return
+		}
+
+		for _, list := range p.lists(config, commandIDs, rolesField) {
+			*list = updateList(*list, role.ID, add)
+		}
+
+		key := "configure.role-removed"
+		if add {
+			key = "configure.role-added"
+		}
+		p.save(bot, client, message, config, l.T(key, role.Name, describeTarget(l, commandIDs)))
+	}
+}
+
+func (p *configurePlugin) listChannels(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	lines := []string{}
+	for i, list := range p.lists(config, commandIDs, channelsField) {
+		value := l.T("configure.unrestricted")
+		if len(*list) > 0 {
+			value = channelMentions(*list)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", describeTarget(l, commandIDAt(commandIDs, i)), value))
+	}
+
+	client.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+func (p *configurePlugin) listRoles(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	lines := []string{}
+	for i, list := range p.lists(config, commandIDs, rolesField) {
+		value := l.T("configure.unrestricted")
+		if len(*list) > 0 {
+			names := []string{}
+			for _, roleID := range *list {
+				if role := client.FindGuildRole(config.GuildID, roleID); role != nil {
+					names = append(names, "@"+role.Name)
+				} else {
+					names = append(names, roleID)
+				}
+			}
+			value = strings.Join(names, ", ")
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", describeTarget(l, commandIDAt(commandIDs, i)), value))
+	}
+
+	client.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+func (p *configurePlugin) setHelp(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	help := &HelpConfiguration{
+		Mode: HelpMode(strings.ToLower(args["mode"])),
+	}
+	if help.Mode == HelpModeEphemeral {
+		help.DeleteAfter = defaultHelpDeleteAfter
+		if seconds, err := strconv.Atoi(args["seconds"]); err == nil && seconds > 0 {
+			help.DeleteAfter = seconds
+		}
+	}
+
+	if _, ok := args["channel"]; !ok {
+		config.Help = help
+		p.save(bot, client, message, config, l.T("configure.help-set", describeHelp(l, help)))
+		return
+	}
+
+	channel := client.FindGuildChannel(config.GuildID, trimMention(args["channel"], "<#"))
+	if channel == nil {
+		client.SendMessage(message.Channel(), l.T("configure.unknown-channel", args["channel"]))
+		return
+	}
+
+	config.setChannelHelp(channel.ID, help)
+	p.save(bot, client, message, config, l.T("configure.channel-help-set", channel.ID, describeHelp(l, help)))
+}
+
+func (p *configurePlugin) removeChannelHelp(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	channel := client.FindGuildChannel(config.GuildID, trimMention(args["channel"], "<#"))
+	if channel == nil {
+		client.SendMessage(message.Channel(), l.T("configure.unknown-channel", args["channel"]))
+		return
+	}
+
+	config.setChannelHelp(channel.ID, nil)
+	p.save(bot, client, message, config, l.T("configure.channel-help-removed", channel.ID, describeHelp(l, config.helpConfiguration(channel.ID))))
+}
+
+func (p *configurePlugin) showHelp(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s: %s", l.T("configure.all-channels"), describeHelp(l, config.helpConfiguration(""))),
+	}
+
+	channelIDs := []string{}
+	for channelID := range config.ChannelHelp {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+
+	for _, channelID := range channelIDs {
+		lines = append(lines, fmt.Sprintf("<#%s>: %s", channelID, describeHelp(l, config.ChannelHelp[channelID])))
+	}
+
+	client.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+// resolvePlugin finds the name of the plugin a moderator referred to.
+func (p *configurePlugin) resolvePlugin(bot *Bot, name string) (string, bool) {
+	for _, plugin := range bot.orderedPlugins(false) {
+		if strings.EqualFold(plugin.Name(), name) {
+			return plugin.Name(), true
+		}
+	}
+	return "", false
+}
+
+func (p *configurePlugin) setPluginEnabled(enabled bool) func(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	return func(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+		l := bot.Localizer(message)
+
+		name, ok := p.resolvePlugin(bot, args["plugin"])
+		if !ok {
+			client.SendMessage(message.Channel(), l.T("configure.unknown-plugin", args["plugin"]))
+			return
+		}
+
+		if protectedPlugins[name] {
+			client.SendMessage(message.Channel(), l.T("configure.plugin-protected", name))
+			return
+		}
+
+		config := p.guildConfiguration(bot, client, message)
+		if config == nil {
+			return
+		}
+
+		config.DisabledPlugins = updateList(config.DisabledPlugins, name, !enabled)
+		if len(config.AllowedPlugins) > 0 {
+			config.AllowedPlugins = updateList(config.AllowedPlugins, name, enabled)
+		}
+
+		key := "configure.plugin-disabled"
+		if enabled {
+			key = "configure.plugin-enabled"
+		}
+		p.save(bot, client, message, config, l.T(key, name))
+	}
+}
+
+func (p *configurePlugin) setAllowedPlugins(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	names := []string{}
+	if !strings.EqualFold(strings.TrimSpace(args["plugins"]), "all") {
+		for _, field := range strings.FieldsFunc(args["plugins"], func(r rune) bool { return r == ' ' || r == ',' }) {
+			name, ok := p.resolvePlugin(bot, field)
+			if !ok {
+				client.SendMessage(message.Channel(), l.T("configure.unknown-plugin", field))
+				return
+			}
+			if !protectedPlugins[name] {
+				names = updateList(names, name, true)
+			}
+		}
+	}
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	config.AllowedPlugins = names
+	for _, name := range names {
+		config.DisabledPlugins = updateList(config.DisabledPlugins, name, false)
+	}
+
+	if len(names) == 0 {
+		p.save(bot, client, message, config, l.T("configure.plugins-all"))
+		return
+	}
+	p.save(bot, client, message, config, l.T("configure.plugins-only", strings.Join(names, ", ")))
+}
+
+func (p *configurePlugin) listPlugins(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	lines := []string{}
+	for _, plugin := range bot.orderedPlugins(false) {
+		state := l.T("configure.plugin-state.enabled")
+		if protectedPlugins[plugin.Name()] {
+			state = l.T("configure.plugin-state.protected")
+		} else if !config.pluginEnabled(plugin.Name()) {
+			state = l.T("configure.plugin-state.disabled")
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", plugin.Name(), state))
+	}
+
+	client.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+// setting returns the setting a moderator referred to, replying with an error if the plugin has no such setting.
+func (p *configurePlugin) setting(bot *Bot, client *Discord, message Message, args map[string]string) (Setting, bool) {
+	setting, ok := findSetting(bot.Plugins[args["plugin"]], args["key"])
+	if !ok {
+		client.SendMessage(message.Channel(), bot.Localizer(message).T("configure.unknown-setting", args["plugin"], args["key"]))
+	}
+	return setting, ok
+}
+
+func (p *configurePlugin) setSetting(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	setting, ok := p.setting(bot, client, message, args)
+	if !ok {
+		return
+	}
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	value, err := setting.parse(client, config.GuildID, l, args["value"])
+	if err != nil {
+		client.SendMessage(message.Channel(), err.Error())
+		return
+	}
+
+	config.setPluginSetting(args["plugin"], setting.Key, value)
+	p.save(bot, client, message, config, l.T("configure.setting-set", args["plugin"], setting.Key, setting.display(client, config.GuildID, l, value)))
+}
+
+func (p *configurePlugin) resetSetting(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	setting, ok := p.setting(bot, client, message, args)
+	if !ok {
+		return
+	}
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	config.resetPluginSetting(args["plugin"], setting.Key)
+	p.save(bot, client, message, config, l.T("configure.setting-reset", args["plugin"], setting.Key, setting.display(client, config.GuildID, l, setting.Default)))
+}
+
+func (p *configurePlugin) showSettings(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	l := bot.Localizer(message)
+
+	config := p.guildConfiguration(bot, client, message)
+	if config == nil {
+		return
+	}
+
+	lines := []string{}
+	for _, setting := range PluginSettings(bot.Plugins[args["plugin"]]) {
+		value, ok := config.pluginSetting(args["plugin"], setting.Key)
+		display := setting.display(client, config.GuildID, l, value)
+		if !ok {
+			display = l.T("configure.setting.default", setting.display(client, config.GuildID, l, setting.Default))
+		}
+
+		line := fmt.Sprintf("`%s`: %s", setting.Key, display)
+		if setting.Description != "" {
+			line = fmt.Sprintf("%s - %s", line, l.T(setting.Description))
+		}
+		if setting.Type == SettingTypeChoice {
+			line = fmt.Sprintf("%s (%s)", line, strings.Join(setting.Choices, ", "))
+		}
+		lines = append(lines, line)
+	}
+
+	client.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+func (p *configurePlugin) setEnabled(enabled bool) func(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+	return func(bot *Bot, client *Discord, message Message, args map[string]string, commandIDs []string) {
+		l := bot.Localizer(message)
+
+		config := p.guildConfiguration(bot, client, message)
+		if config == nil {
+			return
+		}
+
+		for _, commandID := range commandIDs {
+			config.commandConfiguration(commandID).Enabled = enabled
+		}
+
+		key := "configure.command-disabled"
+		if enabled {
+			key = "configure.command-enabled"
+		}
+		p.save(bot, client, message, config, l.T(key, describeTarget(l, commandIDs)))
+	}
+}
+
+// describeTarget names the commands a configure command applied to, or the whole server.
+func describeTarget(l *Localizer, commandIDs []string) string {
+	if commandIDs == nil {
+		return l.T("configure.all-commands")
+	}
+	return "`" + strings.Join(commandIDs, "`, `") + "`"
+}
+
+// describeHelp names a help delivery mode.
+func describeHelp(l *Localizer, help *HelpConfiguration) string {
+	if help.Mode == HelpModeEphemeral {
+		return l.N("configure.help-mode.ephemeral", help.DeleteAfter, help.DeleteAfter)
+	}
+	return l.T("configure.help-mode." + string(help.Mode))
+}
+
+func commandIDAt(commandIDs []string, i int) []string {
+	if commandIDs == nil {
+		return nil
+	}
+	return commandIDs[i : i+1]
+}
+
+func trimMention(value string, prefix string) string {
+	if strings.HasPrefix(value, prefix) && strings.HasSuffix(value, ">") {
+		return value[len(prefix) : len(value)-1]
+	}
+	return value
+}
+
+func updateList(list []string, value string, add bool) []string {
+	updated := []string{}
+	for _, v := range list {
+		if v != value {
+			updated = append(updated, v)
+		}
+	}
+	if add {
+		updated = append(updated, value)
+	}
+	return updated
+}
+
+// NewConfigurePlugin will create a new configure plugin, which lets moderators restrict commands in their server.
+func NewConfigurePlugin() Plugin {
+	return &configurePlugin{}
+}
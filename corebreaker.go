@@ -0,0 +1,171 @@
+package mutterblack
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// BreakerState is the state of the circuit breaker of a core command group.
+type BreakerState string
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen fails every request without sending it, until the cooldown has passed.
+	BreakerOpen = "open"
+	// BreakerHalfOpen lets a single request through to find out whether the group has recovered.
+	BreakerHalfOpen = "half-open"
+)
+
+// CoreGroupStatus is the state of the circuit breaker of one core command group.
+type CoreGroupStatus struct {
+	Group string
+	State BreakerState
+	// Failures is the number of requests in a row that couldn't reach the group.
+	Failures int
+	// RetryAt is when an open breaker lets a request through again.
+	RetryAt time.Time
+}
+
+// circuitBreaker stops sending requests to a command group after threshold requests in a row failed to reach it.
+// Once cooldown has passed one request is let through as a probe, the breaker closes if it succeeds and opens again if it fails.
+type circuitBreaker struct {
+	sync.Mutex
+	group     string
+	threshold int
+	cooldown  time.Duration
+
+	state    BreakerState
+	failures int
+	openedAt time.Time
+}
+
+// allow returns whether a request may be sent now.
+func (b *circuitBreaker) allow() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		log.Printf("Core %s breaker is half-open, probing", b.group)
+		return true
+	case BreakerHalfOpen:
+		// A probe is already on its way.
+		return false
+	}
+	return true
+}
+
+// success records a request that reached the group, whatever it answered.
+func (b *circuitBreaker) success() {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.state != BreakerClosed {
+		log.Printf("Core %s breaker closed", b.group)
+	}
+	b.state = BreakerClosed
+	b.failures = 0
+}
+
+// failure records a request that couldn't reach the group.
+// Requests sent before the breaker opened can still fail once it is open, they don't push back the probe.
+func (b *circuitBreaker) failure() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.failures++
+	if b.state == BreakerOpen {
+		return
+	}
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		log.Printf("Core %s breaker opened after %d failures", b.group, b.failures)
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) status() CoreGroupStatus {
+	b.Lock()
+	defer b.Unlock()
+
+	status := CoreGroupStatus{
+		Group:    b.group,
+		State:    b.state,
+		Failures: b.failures,
+	}
+	if b.state == BreakerOpen {
+		status.RetryAt = b.openedAt.Add(b.cooldown)
+	}
+	return status
+}
+
+// coreGroup returns the command group of a path, the group of commands is named in their path and other paths are grouped by their first part, eg. configuration.
+func coreGroup(path string) string {
+	parts := strings.Split(path, "/")
+	if parts[0] == "command" && len(parts) > 1 {
+		return parts[1]
+	}
+	return parts[0]
+}
+
+// breaker returns the circuit breaker of a command group, creating it the first time the group is used.
+func (c *CoreClient) breaker(group string) *circuitBreaker {
+	c.breakersLock.Lock()
+	defer c.breakersLock.Unlock()
+
+	b, ok := c.breakers[group]
+	if !ok {
+		b = &circuitBreaker{
+			group:     group,
+			threshold: c.breakerThreshold,
+			cooldown:  c.breakerCooldown,
+			state:     BreakerClosed,
+		}
+		c.breakers[group] = b
+	}
+	return b
+}
+
+// GroupStatus returns the state of every command group that has been used, sorted by name.
+func (c *CoreClient) GroupStatus() []CoreGroupStatus {
+	c.breakersLock.Lock()
+	breakers := []*circuitBreaker{}
+	for _, b := range c.breakers {
+		breakers = append(breakers, b)
+	}
+	c.breakersLock.Unlock()
+
+	statuses := []CoreGroupStatus{}
+	for _, b := range breakers {
+		statuses = append(statuses, b.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Group < statuses[j].Group })
+
+	return statuses
+}
+
+// IsCoreUnavailable returns whether err is returned because the circuit breaker of a command group is open, so plugins can say the feature is temporarily unavailable.
+func IsCoreUnavailable(err error) bool {
+	return err != nil && err.Error() == CoreUnavailableFailure
+}
+
+// CoreErrorMessage returns the reply to a failed core command, the message under unavailableKey while the core can't be reached or else the translated error.
+func CoreErrorMessage(l *Localizer, err error, unavailableKey string) string {
+	if IsCoreUnavailable(err) {
+		return l.T(unavailableKey)
+	}
+	return l.T(err.Error())
+}
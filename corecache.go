@@ -0,0 +1,252 @@
+package mutterblack
+
+import (
+	"container/list"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultCoreCacheEntries  = 1000
+	defaultCoreCacheSize     = 8 << 20
+	defaultCoreCacheStaleFor = time.Hour
+)
+
+// defaultCoreCacheTTLs is how long the results of popular lookups are fresh, keyed by command group and action.
+// Actions without a TTL are not cached.
+var defaultCoreCacheTTLs = map[string]time.Duration{
+	"weather/current":              10 * time.Minute,
+	"weather/forecast":             30 * time.Minute,
+	"planetside2/character":        5 * time.Minute,
+	"planetside2/character-weapon": 5 * time.Minute,
+	"planetside2/outfit":           10 * time.Minute,
+}
+
+// CommandResult is the result of a core command and whether it came from the cache.
+type CommandResult struct {
+	Data json.RawMessage
+	// Cached is set when the result came from the cache instead of the core.
+	Cached bool
+	// Stale is set when the cached result is older than the TTL of its action, a fresh one is being fetched in the background.
+	Stale bool
+	// Fetched is when the core returned the result.
+	Fetched time.Time
+}
+
+// AddStaleFooter adds a note to the footer of embed that its data is from the cache when the result is stale.
+func (r *CommandResult) AddStaleFooter(embed *discordgo.MessageEmbed, l *Localizer) {
+	if r == nil || !r.Stale {
+		return
+	}
+
+	minutes := int(time.Since(r.Fetched).Minutes())
+	note := l.N("core.stale-footer", minutes, minutes)
+	if embed.Footer == nil {
+		embed.Footer = &discordgo.MessageEmbedFooter{}
+	}
+	if embed.Footer.Text != "" {
+		note = embed.Footer.Text + " · " + note
+	}
+	embed.Footer.Text = note
+}
+
+type coreCacheEntry struct {
+	key        string
+	data       json.RawMessage
+	fetched    time.Time
+	ttl        time.Duration
+	refreshing bool
+}
+
+// coreCache keeps the results of core commands, evicting the least recently used once it holds too many entries or bytes.
+// Entries older than their TTL are served as stale for staleFor while they are refreshed, after that they are dropped.
+type coreCache struct {
+	sync.Mutex
+	ttls       map[string]time.Duration
+	maxEntries int
+	maxSize    int
+	staleFor   time.Duration
+
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newCoreCache(ttls map[string]time.Duration, maxEntries int, maxSize int, staleFor time.Duration) *coreCache {
+	cache := &coreCache{
+		ttls:       map[string]time.Duration{},
+		maxEntries: maxEntries,
+		maxSize:    maxSize,
+		staleFor:   staleFor,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+	for action, ttl := range defaultCoreCacheTTLs {
+		cache.ttls[action] = ttl
+	}
+	for action, ttl := range ttls {
+		cache.ttls[action] = ttl
+	}
+	return cache
+}
+
+// coreCacheKey returns the key of a command, arguments are sorted and compared without case or extra whitespace.
+func coreCacheKey(commandGroup string, commandAction string, args map[string]string) string {
+	names := []string{}
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := []string{}
+	for _, name := range names {
+		parts = append(parts, name+"="+strings.ToLower(strings.Join(strings.Fields(args[name]), " ")))
+	}
+
+	return commandGroup + "/" + commandAction + "?" + strings.Join(parts, "&")
+}
+
+func (c *coreCache) ttl(commandGroup string, commandAction string) time.Duration {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.ttls[commandGroup+"/"+commandAction]
+}
+
+func (c *coreCache) setTTL(commandGroup string, commandAction string, ttl time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.ttls[commandGroup+"/"+commandAction] = ttl
+}
+
+// get returns the cached result of key, and whether the caller should refresh it because it is stale and nobody else is.
+func (c *coreCache) get(key string) (result *CommandResult, refresh bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*coreCacheEntry)
+
+	age := time.Since(entry.fetched)
+	if age >= entry.ttl+c.staleFor {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	result = &CommandResult{
+		Data:    entry.data,
+		Cached:  true,
+		Stale:   age >= entry.ttl,
+		Fetched: entry.fetched,
+	}
+	if result.Stale && !entry.refreshing {
+		entry.refreshing = true
+		refresh = true
+	}
+	return result, refresh
+}
+
+// put caches data under key, replacing what was there.
+func (c *coreCache) put(key string, data json.RawMessage, ttl time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	if len(data) > c.maxSize {
+		return
+	}
+
+	entry := &coreCacheEntry{
+		key:     key,
+		data:    data,
+		fetched: time.Now(),
+		ttl:     ttl,
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	c.size += len(data)
+
+	for c.order.Len() > c.maxEntries || c.size > c.maxSize {
+		c.remove(c.order.Back())
+	}
+}
+
+// refreshFailed lets the next request for key try to refresh it again.
+func (c *coreCache) refreshFailed(key string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*coreCacheEntry).refreshing = false
+	}
+}
+
+// remove drops an entry, the caller holds the lock.
+func (c *coreCache) remove(element *list.Element) {
+	entry := element.Value.(*coreCacheEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.key)
+	c.size -= len(entry.data)
+}
+
+// CommandResult runs an action of a command group like Command, using the cache for actions with a TTL.
+// A stale result is returned straight away while a fresh one is fetched in the background.
+func (c *CoreClient) CommandResult(commandGroup string, commandAction string, args map[string]string) (*CommandResult, error) {
+	ttl := c.cache.ttl(commandGroup, commandAction)
+	if ttl <= 0 {
+		data, err := c.command(commandGroup, commandAction, args)
+		if err != nil {
+			return nil, err
+		}
+		return &CommandResult{Data: data, Fetched: time.Now()}, nil
+	}
+
+	key := coreCacheKey(commandGroup, commandAction, args)
+	if result, refresh := c.cache.get(key); result != nil {
+		if refresh {
+			copied := make(map[string]string, len(args))
+			for name, value := range args {
+				copied[name] = value
+			}
+			go c.refresh(key, commandGroup, commandAction, copied, ttl)
+		}
+		return result, nil
+	}
+
+	data, err := c.command(commandGroup, commandAction, args)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.put(key, data, ttl)
+
+	return &CommandResult{Data: data, Fetched: time.Now()}, nil
+}
+
+// refresh fetches a stale result again and caches it.
+func (c *CoreClient) refresh(key string, commandGroup string, commandAction string, args map[string]string, ttl time.Duration) {
+	data, err := c.command(commandGroup, commandAction, args)
+	if err != nil {
+		log.Printf("Error refreshing %s. %v", key, err)
+		c.cache.refreshFailed(key)
+		return
+	}
+	c.cache.put(key, data, ttl)
+}
+
+// SetCacheTTL sets how long results of an action are fresh, a TTL of 0 stops caching it.
+func (c *CoreClient) SetCacheTTL(commandGroup string, commandAction string, ttl time.Duration) {
+	c.cache.setTTL(commandGroup, commandAction, ttl)
+}
@@ -5,71 +5,335 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
+	"math/rand"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-const MUTTERBLACK_CORE_URI = "http://mutterblack:5000/"
-
-//const MUTTERBLACK_CORE_URI = "http://localhost:8080/"
+const (
+	defaultCoreURI           = "http://mutterblack:5000/"
+	defaultCoreTimeout       = 10 * time.Second
+	defaultCoreRetries       = 2
+	defaultCoreRetryDelay    = 250 * time.Millisecond
+	defaultCoreMaxRetryDelay = 2 * time.Second
+	// defaultCoreMaxResponseSize is the largest response body read from the core.
+	defaultCoreMaxResponseSize = 4 << 20
+)
 
 type CommandResponse struct {
 	Error  string          `json:"error"`
 	Result json.RawMessage `json:"result"`
 }
 
-func SendCoreCommand(commandGroup string, commandAction string, args map[string]string) (json.RawMessage, error) {
-	var path = "command/" + commandGroup + "/" + commandAction
-	resp, err := handleCoreRequestPost(path, args)
-	return handleResponse(path, resp, err)
+// CoreConfig describes the core service a CoreClient talks to.
+type CoreConfig struct {
+	// URI is the base address every path is added to, defaults to http://mutterblack:5000/.
+	URI string
+	// Timeout is the longest a single attempt of a request may take, defaults to 10 seconds.
+	Timeout time.Duration
+	// Headers are sent with every request, eg. an API key.
+	Headers map[string]string
+	// Retries is how many times an idempotent request is sent again after it failed, defaults to 2. A negative value disables retries.
+	Retries int
+	// RetryDelay is the most the first retry waits, it doubles for every retry after it, defaults to 250 milliseconds.
+	RetryDelay time.Duration
+	// MaxRetryDelay caps the wait before a retry, defaults to 2 seconds.
+	MaxRetryDelay time.Duration
+	// MaxResponseSize is the largest response accepted in bytes, defaults to 4MB.
+	MaxResponseSize int64
+	// BreakerThreshold is how many requests in a row have to fail to reach a command group before requests to it fail fast, defaults to 5.
+	BreakerThreshold int
+	// BreakerCooldown is how long requests to a command group fail fast before one is let through to probe it, defaults to 30 seconds.
+	BreakerCooldown time.Duration
+	// CacheTTLs is how long command results are fresh, keyed by group and action like "weather/current".
+	// They are added to the TTLs of popular lookups, a TTL of 0 stops caching an action.
+	CacheTTLs map[string]time.Duration
+	// CacheEntries is the most results kept in the cache, defaults to 1000.
+	CacheEntries int
+	// CacheSize is the most bytes of results kept in the cache, defaults to 8MB.
+	CacheSize int
+	// CacheStaleFor is how long a result older than its TTL is still served while it is refreshed, defaults to an hour.
+	CacheStaleFor time.Duration
 }
 
-func SendCoreGet(path string) (json.RawMessage, error) {
-	resp, err := handleCoreRequestGet(path)
-	return handleResponse(path, resp, err)
+// CoreClient sends commands and data requests to the core service, the bot's client is Bot.Core.
+type CoreClient struct {
+	uri             string
+	headers         map[string]string
+	client          *http.Client
+	retries         int
+	retryDelay      time.Duration
+	maxRetryDelay   time.Duration
+	maxResponseSize int64
+
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	breakersLock     sync.Mutex
+	breakers         map[string]*circuitBreaker
+
+	cache *coreCache
+
+	guildConfigurationsLock sync.RWMutex
+	guildConfigurations     map[string]cachedGuildConfiguration
 }
 
-func SendCorePost(path string, content interface{}) (json.RawMessage, error) {
-	resp, err := handleCoreRequestPost(path, content)
-	return handleResponse(path, resp, err)
+// NewCoreClient returns a client for the core described by config.
+func NewCoreClient(config CoreConfig) *CoreClient {
+	if config.URI == "" {
+		config.URI = defaultCoreURI
+	}
+	if !strings.HasSuffix(config.URI, "/") {
+		config.URI += "/"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultCoreTimeout
+	}
+	if config.Retries == 0 {
+		config.Retries = defaultCoreRetries
+	} else if config.Retries < 0 {
+		config.Retries = 0
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = defaultCoreRetryDelay
+	}
+	if config.MaxRetryDelay <= 0 {
+		config.MaxRetryDelay = defaultCoreMaxRetryDelay
+	}
+	if config.MaxResponseSize <= 0 {
+		config.MaxResponseSize = defaultCoreMaxResponseSize
+	}
+	if config.BreakerThreshold <= 0 {
+		config.BreakerThreshold = defaultBreakerThreshold
+	}
+	if config.BreakerCooldown <= 0 {
+		config.BreakerCooldown = defaultBreakerCooldown
+	}
+	if config.CacheEntries <= 0 {
+		config.CacheEntries = defaultCoreCacheEntries
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = defaultCoreCacheSize
+	}
+	if config.CacheStaleFor <= 0 {
+		config.CacheStaleFor = defaultCoreCacheStaleFor
+	}
+
+	headers := map[string]string{}
+	for name, value := range config.Headers {
+		headers[name] = value
+	}
+
+	return &CoreClient{
+		uri:                 config.URI,
+		headers:             headers,
+		client:              &http.Client{Timeout: config.Timeout},
+		retries:             config.Retries,
+		retryDelay:          config.RetryDelay,
+		maxRetryDelay:       config.MaxRetryDelay,
+		maxResponseSize:     config.MaxResponseSize,
+		breakerThreshold:    config.BreakerThreshold,
+		breakerCooldown:     config.BreakerCooldown,
+		breakers:            make(map[string]*circuitBreaker),
+		cache:               newCoreCache(config.CacheTTLs, config.CacheEntries, config.CacheSize, config.CacheStaleFor),
+		guildConfigurations: make(map[string]cachedGuildConfiguration),
+	}
 }
 
-func handleResponse(path string, resp *http.Response, err error) (json.RawMessage, error) {
-	if err != nil {
-		log.Println(err)
-		return nil, errors.New(InterProcessCommunicationFailure)
+// CoreConfigFromEnvironment reads the core to use from MUTTERBLACK_CORE_URI, MUTTERBLACK_CORE_TIMEOUT in seconds,
+// MUTTERBLACK_CORE_RETRIES and MUTTERBLACK_CORE_HEADERS, a comma separated list of name=value pairs.
+func CoreConfigFromEnvironment() CoreConfig {
+	config := CoreConfig{
+		URI:     strings.TrimSpace(os.Getenv("MUTTERBLACK_CORE_URI")),
+		Headers: map[string]string{},
 	}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	if seconds, err := strconv.Atoi(os.Getenv("MUTTERBLACK_CORE_TIMEOUT")); err == nil {
+		config.Timeout = time.Duration(seconds) * time.Second
+	}
+	if retries, err := strconv.Atoi(os.Getenv("MUTTERBLACK_CORE_RETRIES")); err == nil {
+		config.Retries = retries
+		if retries == 0 {
+			config.Retries = -1
+		}
+	}
 
-	var commandResponse CommandResponse
-	err = json.Unmarshal(body, &commandResponse)
+	for _, entry := range strings.Split(os.Getenv("MUTTERBLACK_CORE_HEADERS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
 
-	if err != nil {
-		log.Println(fmt.Sprintf("Failed to unmarshal for %v: %v", path, err))
-		return nil, errors.New("Something went wrong :(")
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			log.Printf("Ignoring core header %q, expected name=value", entry)
+			continue
+		}
+		config.Headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
 	}
 
-	if commandResponse.Error != "" {
-		return nil, errors.New(commandResponse.Error)
+	return config
+}
+
+// CoreClientFromEnvironment returns a client for the core described by CoreConfigFromEnvironment.
+func CoreClientFromEnvironment() *CoreClient {
+	return NewCoreClient(CoreConfigFromEnvironment())
+}
+
+// Command runs an action of a command group in the core, eg. the current weather.
+// Results of actions with a cache TTL may come from the cache, use CommandResult to find out whether they are stale.
+func (c *CoreClient) Command(commandGroup string, commandAction string, args map[string]string) (json.RawMessage, error) {
+	result, err := c.CommandResult(commandGroup, commandAction, args)
+	if err != nil {
+		return nil, err
 	}
+	return result.Data, nil
+}
+
+// command sends a command to the core, commands only look things up so they are retried like other idempotent requests.
+func (c *CoreClient) command(commandGroup string, commandAction string, args map[string]string) (json.RawMessage, error) {
+	return c.do(http.MethodPost, "command/"+commandGroup+"/"+commandAction, args, true)
+}
+
+// Get returns the result stored at path.
+func (c *CoreClient) Get(path string) (json.RawMessage, error) {
+	return c.do(http.MethodGet, path, nil, true)
+}
 
-	return commandResponse.Result, nil
+// Post sends content to path as JSON and returns the result, it is never retried.
+func (c *CoreClient) Post(path string, content interface{}) (json.RawMessage, error) {
+	return c.do(http.MethodPost, path, content, false)
 }
 
-func handleCoreRequestGet(path string) (resp *http.Response, err error) {
-	var commandURI = GetURI(path)
-	return http.Get(commandURI)
+// Delete removes what is stored at path.
+func (c *CoreClient) Delete(path string) (json.RawMessage, error) {
+	return c.do(http.MethodDelete, path, nil, true)
 }
 
-func handleCoreRequestPost(path string, content interface{}) (resp *http.Response, err error) {
-	var commandURI = GetURI(path)
-	contentBytes, _ := json.Marshal(content)
-	return http.Post(commandURI, "application/json", bytes.NewBuffer(contentBytes))
+// URI returns the address of path in the core.
+func (c *CoreClient) URI(path string) string {
+	return c.uri + path
 }
 
-func GetURI(path string) string {
-	return MUTTERBLACK_CORE_URI + path
+// do sends a request and returns its result.
+// Idempotent requests that can't connect, time out or get a 5xx or 429 response are retried after a jittered, exponentially growing delay.
+// Requests to a command group whose circuit breaker is open fail fast with CoreUnavailableFailure.
+// Errors returned are catalog keys or messages from the core, so they can be shown to users.
+func (c *CoreClient) do(method string, path string, content interface{}, idempotent bool) (json.RawMessage, error) {
+	var body []byte
+	if content != nil {
+		var err error
+		if body, err = json.Marshal(content); err != nil {
+			log.Printf("Failed to marshal request for %v: %v", path, err)
+			return nil, errors.New(UnexpectedResponseFailure)
+		}
+	}
+
+	breaker := c.breaker(coreGroup(path))
+	if !breaker.allow() {
+		return nil, errors.New(CoreUnavailableFailure)
+	}
+
+	attempts := 1
+	if idempotent {
+		attempts += c.retries
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(c.backoff(attempt))
+		}
+
+		var result json.RawMessage
+		var retry bool
+		result, retry, err = c.attempt(method, path, body)
+		if !retry {
+			breaker.success()
+			return result, err
+		}
+
+		if attempt+1 < attempts {
+			log.Printf("Retrying %s %s, attempt %d of %d failed", method, path, attempt+1, attempts)
+		}
+	}
+
+	breaker.failure()
+	return nil, err
+}
+
+// attempt sends a request once, it returns whether the request may succeed if it is sent again.
+func (c *CoreClient) attempt(method string, path string, body []byte) (result json.RawMessage, retry bool, err error) {
+	req, err := http.NewRequest(method, c.URI(path), bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Failed to create request for %v: %v", path, err)
+		return nil, false, errors.New(UnexpectedResponseFailure)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Printf("Failed to reach the core for %v: %v", path, err)
+		return nil, true, errors.New(InterProcessCommunicationFailure)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize+1))
+	if err != nil {
+		log.Printf("Failed to read response for %v: %v", path, err)
+		return nil, true, errors.New(InterProcessCommunicationFailure)
+	}
+	if int64(len(data)) > c.maxResponseSize {
+		log.Printf("Response for %v is larger than %d bytes", path, c.maxResponseSize)
+		return nil, false, errors.New(UnexpectedResponseFailure)
+	}
+
+	var commandResponse CommandResponse
+	parseErr := json.Unmarshal(data, &commandResponse)
+
+	switch {
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		log.Printf("Core returned %s for %v", resp.Status, path)
+		return nil, true, errors.New(InterProcessCommunicationFailure)
+	case resp.StatusCode >= 300:
+		log.Printf("Core returned %s for %v", resp.Status, path)
+		if parseErr == nil && commandResponse.Error != "" {
+			return nil, false, errors.New(commandResponse.Error)
+		}
+		return nil, false, errors.New(UnexpectedResponseFailure)
+	}
+
+	if parseErr != nil {
+		log.Println(fmt.Sprintf("Failed to unmarshal for %v: %v", path, parseErr))
+		return nil, false, errors.New(UnexpectedResponseFailure)
+	}
+
+	if commandResponse.Error != "" {
+		return nil, false, errors.New(commandResponse.Error)
+	}
+
+	return commandResponse.Result, false, nil
+}
+
+// backoff returns how long to wait before a retry, a random time up to RetryDelay doubled for every earlier retry and capped at MaxRetryDelay.
+func (c *CoreClient) backoff(retry int) time.Duration {
+	delay := c.retryDelay << uint(retry-1)
+	if delay <= 0 || delay > c.maxRetryDelay {
+		delay = c.maxRetryDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay))) + 1
 }
@@ -0,0 +1,452 @@
+package mutterblack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const dataPluginName = "Data"
+
+// dataConfirmation is the word that has to follow a purge or delete command for it to run.
+const dataConfirmation = "confirm"
+
+var userMentionPattern = regexp.MustCompile(`^<@!?(\d+)>$`)
+
+type dataPlugin struct{}
+
+// dataExport is everything the bot holds for a guild or a user.
+type dataExport struct {
+	GuildID       string                       `json:"guildId,omitempty"`
+	UserID        string                       `json:"userId,omitempty"`
+	Exported      time.Time                    `json:"exported"`
+	Configuration *GuildConfiguration          `json:"configuration,omitempty"`
+	Plugins       map[string]*pluginDataExport `json:"plugins,omitempty"`
+	// Errors lists what couldn't be exported, the export holds everything else.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// pluginDataExport is what one plugin holds for a guild or a user.
+// Members is keyed by user ID in a guild export and by guild ID in a user export.
+type pluginDataExport struct {
+	Guild   map[string]json.RawMessage            `json:"guild,omitempty"`
+	User    map[string]json.RawMessage            `json:"user,omitempty"`
+	Members map[string]map[string]json.RawMessage `json:"members,omitempty"`
+	Data    interface{}                           `json:"data,omitempty"`
+}
+
+func (e *pluginDataExport) empty() bool {
+	return len(e.Guild) == 0 && len(e.User) == 0 && len(e.Members) == 0 && e.Data == nil
+}
+
+func (p *dataPlugin) Name() string {
+	return dataPluginName
+}
+
+func (p *dataPlugin) Commands() []CommandDefinition {
+	return []CommandDefinition{
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "data-guild-export",
+			Triggers: []string{
+				"exportguilddata",
+			},
+			Description: "data.command.guild-export",
+			Callback:    p.runGuildExportCommand,
+		},
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "data-guild-purge",
+			Triggers: []string{
+				"purgeguilddata",
+			},
+			Arguments: []CommandDefinitionArgument{
+				CommandDefinitionArgument{
+					Optional:    true,
+					Pattern:     dataConfirmation,
+					Alias:       "confirm",
+					Type:        ArgumentTypeWord,
+					Description: "data.argument.confirm",
+				},
+			},
+			Examples: []string{
+				dataConfirmation,
+			},
+			Description: "data.command.guild-purge",
+			Callback:    p.runGuildPurgeCommand,
+		},
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "data-user-export",
+			Triggers: []string{
+				"exportuserdata",
+			},
+			Arguments: []CommandDefinitionArgument{
+				CommandDefinitionArgument{
+					Optional:    true,
+					Pattern:     "<@!?[0-9]+>|[0-9]+",
+					Alias:       "user",
+					Type:        ArgumentTypeWord,
+					Description: "data.argument.user",
+				},
+			},
+			Description: "data.command.user-export",
+			Callback:    p.runUserExportCommand,
+		},
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "data-user-delete",
+			Triggers: []string{
+				"deleteuserdata",
+			},
+			Arguments: []CommandDefinitionArgument{
+				CommandDefinitionArgument{
+					Optional:    true,
+					Pattern:     dataConfirmation,
+					Alias:       "confirm",
+					Type:        ArgumentTypeWord,
+					Description: "data.argument.confirm",
+				},
+				CommandDefinitionArgument{
+					Optional:    true,
+					Pattern:     "<@!?[0-9]+>|[0-9]+",
+					Alias:       "user",
+					Type:        ArgumentTypeWord,
+					Description: "data.argument.user",
+				},
+			},
+			Examples: []string{
+				dataConfirmation,
+			},
+			Description: "data.command.user-delete",
+			Callback:    p.runUserDeleteCommand,
+		},
+	}
+}
+
+// targetGuild returns the guild of a message if its author owns it, the bot owner counts as the owner of every guild.
+func (p *dataPlugin) targetGuild(bot *Bot, client *Discord, message Message) (string, bool) {
+	l := bot.Localizer(message)
+
+	guildID := client.ChannelGuildID(message.Channel())
+	if guildID == "" {
+		client.SendMessage(message.Channel(), l.T("data.guild-only"))
+		return "", false
+	}
+	if !client.IsChannelOwner(message) {
+		client.SendMessage(message.Channel(), l.T("data.guild-owner-only"))
+		return "", false
+	}
+	return guildID, true
+}
+
+// targetUser returns the user a user command acts on and whether the author may use it, only the bot owner can name another user.
+func (p *dataPlugin) targetUser(bot *Bot, client *Discord, message Message, args map[string]string) (string, bool) {
+	userID := args["user"]
+	if match := userMentionPattern.FindStringSubmatch(userID); match != nil {
+		userID = match[1]
+	}
+
+	if userID == "" || userID == message.UserID() {
+		return message.UserID(), true
+	}
+
+	if !client.IsBotOwner(message) {
+		client.SendMessage(message.Channel(), bot.Localizer(message).T("error.owner-only"))
+		return "", false
+	}
+	return userID, true
+}
+
+func (p *dataPlugin) runGuildExportCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	guildID, ok := p.targetGuild(bot, client, message)
+	if !ok {
+		return
+	}
+
+	p.sendExport(bot, client, message, fmt.Sprintf("guild-%s.json", guildID), bot.exportGuildData(guildID))
+}
+
+func (p *dataPlugin) runGuildPurgeCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	l := bot.Localizer(message)
+
+	guildID, ok := p.targetGuild(bot, client, message)
+	if !ok {
+		return
+	}
+
+	if args["confirm"] != dataConfirmation {
+		client.SendMessage(message.Channel(), l.T("data.guild-purge-confirm", client.MessageCommandPrefix(message)+trigger, dataConfirmation))
+		return
+	}
+
+	if err := bot.purgeGuildData(guildID); err != nil {
+		log.Printf("Error purging data of guild %s. %v", guildID, err)
+		client.SendMessage(message.Channel(), l.T("data.purge-failed"))
+		return
+	}
+
+	client.SendMessage(message.Channel(), l.T("data.guild-purged"))
+}
+
+func (p *dataPlugin) runUserExportCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	userID, ok := p.targetUser(bot, client, message, args)
+	if !ok {
+		return
+	}
+
+	p.sendExport(bot, client, message, fmt.Sprintf("user-%s.json", userID), bot.exportUserData(userID))
+}
+
+func (p *dataPlugin) runUserDeleteCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	l := bot.Localizer(message)
+
+	userID, ok := p.targetUser(bot, client, message, args)
+	if !ok {
+		return
+	}
+
+	if args["confirm"] != dataConfirmation {
+		client.SendMessage(message.Channel(), l.T("data.user-delete-confirm", client.MessageCommandPrefix(message)+trigger, dataConfirmation))
+		return
+	}
+
+	if err := bot.deleteUserData(userID); err != nil {
+		log.Printf("Error deleting data of user %s. %v", userID, err)
+		client.SendMessage(message.Channel(), l.T("data.purge-failed"))
+		return
+	}
+
+	client.SendMessage(message.Channel(), l.T("data.user-deleted"))
+}
+
+// sendExport sends an export as a JSON file to the author privately, so it never shows up in a guild channel.
+func (p *dataPlugin) sendExport(bot *Bot, client *Discord, message Message, name string, export *dataExport) {
+	l := bot.Localizer(message)
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		log.Printf("Error encoding export %s. %v", name, err)
+		client.SendMessage(message.Channel(), l.T("data.export-failed"))
+		return
+	}
+
+	channel, err := client.PrivateChannelID(message.UserID())
+	if err != nil {
+		client.SendMessage(message.Channel(), l.T("data.export-failed"))
+		return
+	}
+
+	if err := client.SendFile(channel, name, bytes.NewReader(data)); err != nil {
+		client.SendMessage(message.Channel(), l.T("data.export-failed"))
+		return
+	}
+
+	if len(export.Errors) > 0 {
+		log.Printf("Export %s is incomplete. %s", name, strings.Join(export.Errors, "; "))
+		client.SendMessage(message.Channel(), l.T("data.export-incomplete"))
+	} else if channel != message.Channel() {
+		client.SendMessage(message.Channel(), l.T("data.export-sent"))
+	}
+}
+
+// exportGuildData collects the configuration of a guild, the guild and member buckets of every plugin's storage and what GuildDataProviders hold.
+// Anything that can't be loaded is listed in the export's Errors.
+func (b *Bot) exportGuildData(guildID string) *dataExport {
+	export := &dataExport{
+		GuildID:       guildID,
+		Exported:      time.Now().UTC(),
+		Configuration: b.Core.findGuildConfiguration(guildID),
+		Plugins:       map[string]*pluginDataExport{},
+	}
+	if export.Configuration == nil {
+		export.Errors = append(export.Errors, "configuration: couldn't be loaded")
+	}
+
+	for _, plugin := range b.orderedPlugins(false) {
+		pluginExport := &pluginDataExport{}
+
+		buckets := b.pluginStore(plugin.Name()).matching(func(namespace storageNamespace) bool {
+			return namespace.guildID == guildID
+		})
+		for namespace, bucket := range buckets {
+			if namespace.userID == "" {
+				pluginExport.Guild = bucket
+				continue
+			}
+			if pluginExport.Members == nil {
+				pluginExport.Members = map[string]map[string]json.RawMessage{}
+			}
+			pluginExport.Members[namespace.userID] = bucket
+		}
+
+		if provider, ok := plugin.(GuildDataProvider); ok {
+			data, err := provider.GuildData(b, b.Client, guildID)
+			if err != nil {
+				export.Errors = append(export.Errors, fmt.Sprintf("%s: %v", plugin.Name(), err))
+			}
+			pluginExport.Data = data
+		}
+
+		if !pluginExport.empty() {
+			export.Plugins[plugin.Name()] = pluginExport
+		}
+	}
+
+	return export
+}
+
+// purgeGuildData removes everything exportGuildData collects, saves the bot and deletes the backups that still hold the removed data.
+// A part that fails doesn't stop the others, what was removed is always saved and the failures are returned together.
+func (b *Bot) purgeGuildData(guildID string) error {
+	removeErr := &dataRemovalError{}
+	names := map[string]bool{}
+	for _, plugin := range b.orderedPlugins(false) {
+		if b.pluginStore(plugin.Name()).removeMatching(func(namespace storageNamespace) bool {
+			return namespace.guildID == guildID
+		}) {
+			names[plugin.Name()+storageSuffix] = true
+		}
+
+		if provider, ok := plugin.(GuildDataProvider); ok {
+			if err := provider.DeleteGuildData(b, b.Client, guildID); err != nil {
+				removeErr.add(plugin.Name(), err)
+			}
+			addProviderDataNames(names, plugin)
+		}
+	}
+
+	if err := b.Core.deleteGuildConfiguration(guildID); err != nil {
+		removeErr.add("configuration", err)
+	}
+
+	if err := b.saveRemoved(names); err != nil {
+		removeErr.add("saving", err)
+	}
+	return removeErr.err()
+}
+
+// exportUserData collects the user and member buckets of every plugin's storage and what UserDataProviders hold for a user.
+// Anything that can't be loaded is listed in the export's Errors.
+func (b *Bot) exportUserData(userID string) *dataExport {
+	export := &dataExport{
+		UserID:   userID,
+		Exported: time.Now().UTC(),
+		Plugins:  map[string]*pluginDataExport{},
+	}
+
+	for _, plugin := range b.orderedPlugins(false) {
+		pluginExport := &pluginDataExport{}
+
+		buckets := b.pluginStore(plugin.Name()).matching(func(namespace storageNamespace) bool {
+			return namespace.userID == userID
+		})
+		for namespace, bucket := range buckets {
+			if namespace.guildID == "" {
+				pluginExport.User = bucket
+				continue
+			}
+			if pluginExport.Members == nil {
+				pluginExport.Members = map[string]map[string]json.RawMessage{}
+			}
+			pluginExport.Members[namespace.guildID] = bucket
+		}
+
+		if provider, ok := plugin.(UserDataProvider); ok {
+			data, err := provider.UserData(b, b.Client, userID)
+			if err != nil {
+				export.Errors = append(export.Errors, fmt.Sprintf("%s: %v", plugin.Name(), err))
+			}
+			pluginExport.Data = data
+		}
+
+		if !pluginExport.empty() {
+			export.Plugins[plugin.Name()] = pluginExport
+		}
+	}
+
+	return export
+}
+
+// deleteUserData removes everything exportUserData collects, saves the bot and deletes the backups that still hold the removed data.
+// Like purgeGuildData, a part that fails doesn't stop the others.
+func (b *Bot) deleteUserData(userID string) error {
+	removeErr := &dataRemovalError{}
+	names := map[string]bool{}
+	for _, plugin := range b.orderedPlugins(false) {
+		if b.pluginStore(plugin.Name()).removeMatching(func(namespace storageNamespace) bool {
+			return namespace.userID == userID
+		}) {
+			names[plugin.Name()+storageSuffix] = true
+		}
+
+		if provider, ok := plugin.(UserDataProvider); ok {
+			if err := provider.DeleteUserData(b, b.Client, userID); err != nil {
+				removeErr.add(plugin.Name(), err)
+			}
+			addProviderDataNames(names, plugin)
+		}
+	}
+
+	if err := b.saveRemoved(names); err != nil {
+		removeErr.add("saving", err)
+	}
+	return removeErr.err()
+}
+
+// dataRemovalError collects the parts of a purge or delete that failed.
+type dataRemovalError struct {
+	errors []string
+}
+
+func (e *dataRemovalError) add(part string, err error) {
+	e.errors = append(e.errors, fmt.Sprintf("%s: %v", part, err))
+}
+
+// err returns e if anything failed, or nil.
+func (e *dataRemovalError) err() error {
+	if len(e.errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *dataRemovalError) Error() string {
+	return strings.Join(e.errors, "; ")
+}
+
+// addProviderDataNames adds the names a data provider may have kept the removed data under, its storage and the data it saves as a Persister.
+func addProviderDataNames(names map[string]bool, plugin Plugin) {
+	names[plugin.Name()+storageSuffix] = true
+	if _, ok := plugin.(Persister); ok {
+		names[plugin.Name()] = true
+	}
+}
+
+// saveRemoved saves the bot after data was removed on request, then deletes the backups of names so the removed data doesn't survive in them.
+func (b *Bot) saveRemoved(names map[string]bool) error {
+	b.Save()
+	if err := b.SaveStatus().Err; err != nil {
+		return err
+	}
+
+	remover, ok := b.Backend.(BackupRemover)
+	if !ok {
+		return nil
+	}
+	for name := range names {
+		if err := remover.RemoveBackups(name); err != nil {
+			return fmt.Errorf("removing backups of %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// NewDataPlugin will create a new data plugin, which lets guild owners and users export and delete what the bot holds about them.
+func NewDataPlugin() Plugin {
+	return &dataPlugin{}
+}
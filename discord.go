@@ -4,6 +4,8 @@ import (
 	"io"
 	"log"
 	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -15,6 +17,9 @@ type DiscordMessage struct {
 	MessageType      MessageType
 	Nick             *string
 	Content          *string
+
+	guildConfigurationOnce sync.Once
+	guildConfiguration     *GuildConfiguration
 }
 
 func (m *DiscordMessage) Channel() string {
@@ -80,17 +85,27 @@ func (m *DiscordMessage) Timestamp() (time.Time, error) {
 type Discord struct {
 	args        []interface{}
 	messageChan chan Message
+	eventChan   chan interface{}
+	paginators  paginators
+	handlers    []interface{}
 
 	Session             *discordgo.Session
 	Sessions            []*discordgo.Session
 	OwnerUserID         string
 	ApplicationClientID string
+
+	// core is the core client of the bot, it holds the guild configurations.
+	core *CoreClient
 }
 
 func NewDiscord(args ...interface{}) *Discord {
 	return &Discord{
 		args:        args,
 		messageChan: make(chan Message, 200),
+		eventChan:   make(chan interface{}, 200),
+		paginators: paginators{
+			byMessageID: make(map[string]*paginator),
+		},
 	}
 }
 
@@ -165,6 +180,23 @@ func (d *Discord) onMessageDelete(s *discordgo.Session, message *discordgo.Messa
 	}
 }
 
+// onEvent forwards the events plugins can listen for to the bot.
+func (d *Discord) onEvent(s *discordgo.Session, event interface{}) {
+	switch event.(type) {
+	case *discordgo.MessageReactionAdd, *discordgo.MessageReactionRemove,
+		*discordgo.GuildMemberAdd, *discordgo.GuildMemberUpdate, *discordgo.GuildMemberRemove,
+		*discordgo.GuildCreate, *discordgo.GuildDelete,
+		*discordgo.ChannelCreate, *discordgo.ChannelDelete,
+		*discordgo.GuildRoleCreate, *discordgo.GuildRoleUpdate, *discordgo.GuildRoleDelete:
+		d.eventChan <- event
+	}
+}
+
+// Events returns the channel Discord events that plugins can listen for are delivered on.
+func (d *Discord) Events() <-chan interface{} {
+	return d.eventChan
+}
+
 func (d *Discord) UserName() string {
 	if d.Session.State.User == nil {
 		return ""
@@ -202,6 +234,11 @@ func (d *Discord) Open() (<-chan Message, error) {
 		session.AddHandler(d.onMessageCreate)
 		session.AddHandler(d.onMessageUpdate)
 		session.AddHandler(d.onMessageDelete)
+		session.AddHandler(d.onEvent)
+		session.AddHandler(d.onPaginatorReaction)
+		for _, handler := range d.handlers {
+			session.AddHandler(handler)
+		}
 		session.State.TrackPresences = false
 
 		d.Sessions[i] = session
@@ -216,6 +253,26 @@ func (d *Discord) Open() (<-chan Message, error) {
 	return d.messageChan, nil
 }
 
+// AddHandler adds a discordgo event handler to every shard, handlers added before Open are added as the shards are created.
+func (d *Discord) AddHandler(handler interface{}) {
+	d.handlers = append(d.handlers, handler)
+	for _, session := range d.Sessions {
+		session.AddHandler(handler)
+	}
+}
+
+// Close disconnects every shard.
+func (d *Discord) Close() error {
+	var closeErr error
+	for _, session := range d.Sessions {
+		if err := session.Close(); err != nil {
+			log.Printf("Error closing shard %d: %v", session.ShardID, err)
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
 func (d *Discord) IsMe(message Message) bool {
 	if d.Session.State.User == nil {
 		return false
@@ -279,6 +336,17 @@ func (d *Discord) DeleteMessage(channel, messageID string) error {
 	return d.Session.ChannelMessageDelete(channel, messageID)
 }
 
+// DeleteMessagesAfter deletes messages from a channel once delay has passed.
+func (d *Discord) DeleteMessagesAfter(channel string, messageIDs []string, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		for _, messageID := range messageIDs {
+			if err := d.DeleteMessage(channel, messageID); err != nil {
+				log.Println("Error deleting discord message: ", err)
+			}
+		}
+	})
+}
+
 func (d *Discord) SendFile(channel, name string, r io.Reader) error {
 	if _, err := d.Session.ChannelFileSend(channel, name, r); err != nil {
 		log.Println("Error sending discord message: ", err)
@@ -320,6 +388,14 @@ func (d *Discord) PrivateMessage(userID string, message string) error {
 	return d.SendMessage(c.ID, message)
 }
 
+func (d *Discord) PrivateChannelID(userID string) (string, error) {
+	c, err := d.Session.UserChannelCreate(userID)
+	if err != nil {
+		return "", err
+	}
+	return c.ID, nil
+}
+
 func (d *Discord) IsBotOwner(message Message) bool {
 	return message.UserID() == d.OwnerUserID
 }
@@ -329,6 +405,14 @@ func (d *Discord) IsPrivate(message Message) bool {
 	return err == nil && c.Type == discordgo.ChannelTypeDM
 }
 
+func (d *Discord) ChannelGuildID(channelID string) string {
+	c, err := d.Channel(channelID)
+	if err != nil || c == nil {
+		return ""
+	}
+	return c.GuildID
+}
+
 func (d *Discord) IsChannelOwner(message Message) bool {
 	c, err := d.Channel(message.Channel())
 	if err != nil {
@@ -357,6 +441,46 @@ func (d *Discord) CommandPrefix() string {
 	return "?"
 }
 
+// ChannelCommandPrefix returns the command prefix configured for the guild a channel belongs to.
+func (d *Discord) ChannelCommandPrefix(channelID string) string {
+	return d.configuredPrefix(d.channelGuildConfiguration(channelID))
+}
+
+// MessageCommandPrefix returns the command prefix configured for the guild a message was sent in.
+func (d *Discord) MessageCommandPrefix(message Message) string {
+	return d.configuredPrefix(d.messageGuildConfiguration(message))
+}
+
+func (d *Discord) configuredPrefix(config *GuildConfiguration) string {
+	if config != nil && config.Prefix != "" {
+		return config.Prefix
+	}
+	return d.CommandPrefix()
+}
+
+// channelGuildConfiguration returns the configuration of the guild a channel belongs to, or nil for private channels.
+func (d *Discord) channelGuildConfiguration(channelID string) *GuildConfiguration {
+	guildID := d.ChannelGuildID(channelID)
+	if guildID == "" || d.core == nil {
+		return nil
+	}
+	return d.core.findGuildConfiguration(guildID)
+}
+
+// messageGuildConfiguration returns the configuration of the guild a message was sent in.
+// Every plugin looks at it, so a DiscordMessage looks it up once and keeps it.
+func (d *Discord) messageGuildConfiguration(message Message) *GuildConfiguration {
+	m, ok := message.(*DiscordMessage)
+	if !ok {
+		return d.channelGuildConfiguration(message.Channel())
+	}
+
+	m.guildConfigurationOnce.Do(func() {
+		m.guildConfiguration = d.channelGuildConfiguration(message.Channel())
+	})
+	return m.guildConfiguration
+}
+
 func (d *Discord) ChannelCount() int {
 	return len(d.Guilds())
 }
@@ -398,6 +522,7 @@ func (d *Discord) GetMessages(channelID string, limit int, beforeID string) ([]M
 }
 
 func (d *Discord) Channel(channelID string) (channel *discordgo.Channel, err error) {
+	err = discordgo.ErrStateNotFound
 	for _, s := range d.Sessions {
 		channel, err = s.State.Channel(channelID)
 		if err == nil {
@@ -408,6 +533,7 @@ func (d *Discord) Channel(channelID string) (channel *discordgo.Channel, err err
 }
 
 func (d *Discord) Guild(guildID string) (guild *discordgo.Guild, err error) {
+	err = discordgo.ErrStateNotFound
 	for _, s := range d.Sessions {
 		guild, err = s.State.Guild(guildID)
 		if err == nil {
@@ -426,6 +552,7 @@ func (d *Discord) Guilds() []*discordgo.Guild {
 }
 
 func (d *Discord) UserChannelPermissions(userID, channelID string) (apermissions int, err error) {
+	err = discordgo.ErrStateNotFound
 	for _, s := range d.Sessions {
 		apermissions, err = s.State.UserChannelPermissions(userID, channelID)
 		if err == nil {
@@ -435,6 +562,53 @@ func (d *Discord) UserChannelPermissions(userID, channelID string) (apermissions
 	return
 }
 
+func (d *Discord) UserRoles(guildID, userID string) []string {
+	for _, s := range d.Sessions {
+		member, err := s.State.Member(guildID, userID)
+		if err == nil {
+			return member.Roles
+		}
+	}
+
+	member, err := d.Session.GuildMember(guildID, userID)
+	if err != nil {
+		return nil
+	}
+	return member.Roles
+}
+
+// FindGuildChannel finds a channel in a guild by ID or by name, with or without a leading #.
+func (d *Discord) FindGuildChannel(guildID, channel string) *discordgo.Channel {
+	g, err := d.Guild(guildID)
+	if err != nil || g == nil {
+		return nil
+	}
+
+	name := strings.TrimPrefix(channel, "#")
+	for _, c := range g.Channels {
+		if c.ID == channel || strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// FindGuildRole finds a role in a guild by ID or by name, with or without a leading @.
+func (d *Discord) FindGuildRole(guildID, role string) *discordgo.Role {
+	g, err := d.Guild(guildID)
+	if err != nil || g == nil {
+		return nil
+	}
+
+	name := strings.TrimPrefix(role, "@")
+	for _, r := range g.Roles {
+		if r.ID == role || strings.EqualFold(r.Name, name) {
+			return r
+		}
+	}
+	return nil
+}
+
 func (d *Discord) UserColor(userID, channelID string) int {
 	for _, s := range d.Sessions {
 		color := s.State.UserColor(userID, channelID)
@@ -1,3 +1,8 @@
 package mutterblack
 
-const InterProcessCommunicationFailure = "Failed to communicate with core routines"
+const InterProcessCommunicationFailure = "error.core-communication"
+
+const UnexpectedResponseFailure = "error.unexpected-response"
+
+// CoreUnavailableFailure is returned without calling the core while the circuit breaker of a command group is open.
+const CoreUnavailableFailure = "error.core-unavailable"
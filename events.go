@@ -0,0 +1,188 @@
+package mutterblack
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ReactionAddListener is implemented by plugins that want to know when a reaction is added to a message.
+type ReactionAddListener interface {
+	ReactionAdd(*Bot, *Discord, *discordgo.MessageReaction)
+}
+
+// ReactionRemoveListener is implemented by plugins that want to know when a reaction is removed from a message.
+type ReactionRemoveListener interface {
+	ReactionRemove(*Bot, *Discord, *discordgo.MessageReaction)
+}
+
+// MemberJoinListener is implemented by plugins that want to know when a member joins a guild.
+type MemberJoinListener interface {
+	MemberJoin(*Bot, *Discord, *discordgo.Member)
+}
+
+// MemberUpdateListener is implemented by plugins that want to know when a member's roles or nickname change.
+type MemberUpdateListener interface {
+	MemberUpdate(*Bot, *Discord, *discordgo.Member)
+}
+
+// MemberLeaveListener is implemented by plugins that want to know when a member leaves a guild.
+type MemberLeaveListener interface {
+	MemberLeave(*Bot, *Discord, *discordgo.Member)
+}
+
+// GuildCreateListener is implemented by plugins that want every guild that becomes available, including those loaded when connecting.
+// Use GuildJoinHandler to only hear about guilds the bot was added to.
+type GuildCreateListener interface {
+	GuildCreate(*Bot, *Discord, *discordgo.Guild)
+}
+
+// GuildDeleteListener is implemented by plugins that want to know when a guild is removed or becomes unavailable.
+type GuildDeleteListener interface {
+	GuildDelete(*Bot, *Discord, *discordgo.Guild)
+}
+
+// ChannelCreateListener is implemented by plugins that want to know when a channel is created.
+type ChannelCreateListener interface {
+	ChannelCreate(*Bot, *Discord, *discordgo.Channel)
+}
+
+// ChannelDeleteListener is implemented by plugins that want to know when a channel is deleted.
+type ChannelDeleteListener interface {
+	ChannelDelete(*Bot, *Discord, *discordgo.Channel)
+}
+
+// RoleCreateListener is implemented by plugins that want to know when a role is created.
+type RoleCreateListener interface {
+	RoleCreate(*Bot, *Discord, *discordgo.GuildRole)
+}
+
+// RoleUpdateListener is implemented by plugins that want to know when a role is changed.
+type RoleUpdateListener interface {
+	RoleUpdate(*Bot, *Discord, *discordgo.GuildRole)
+}
+
+// RoleDeleteListener is implemented by plugins that want to know when a role is deleted.
+type RoleDeleteListener interface {
+	RoleDelete(*Bot, *Discord, *discordgo.GuildRoleDelete)
+}
+
+// dispatchEvent calls every plugin listening for an event, in its own goroutine like messages.
+// Plugins disabled for maintenance, or by the guild the event happened in, don't receive events.
+// The guild configuration may have to be fetched from the core, so it is looked up once off the event loop and only if a plugin listens for the event.
+func (b *Bot) dispatchEvent(event interface{}) {
+	plugins := []Plugin{}
+	calls := []func(){}
+	for _, plugin := range b.orderedPlugins(false) {
+		call := b.eventCall(plugin, event)
+		if call == nil || b.maintenance(plugin, nil) != nil {
+			continue
+		}
+		plugins = append(plugins, plugin)
+		calls = append(calls, call)
+	}
+
+	if len(plugins) == 0 {
+		return
+	}
+
+	go func() {
+		var config *GuildConfiguration
+		if guildID := b.eventGuildID(event); guildID != "" {
+			config = b.Core.findGuildConfiguration(guildID)
+		}
+
+		for i, plugin := range plugins {
+			if b.pluginDisabled(plugin, config) {
+				continue
+			}
+			go b.protect(plugin, fmt.Sprintf("%T", event), "", calls[i])
+		}
+	}()
+}
+
+// eventCall returns a call to the listener method of plugin for event, or nil if the plugin doesn't listen for it.
+func (b *Bot) eventCall(plugin Plugin, event interface{}) func() {
+	switch e := event.(type) {
+	case *discordgo.MessageReactionAdd:
+		if l, ok := plugin.(ReactionAddListener); ok {
+			return func() { l.ReactionAdd(b, b.Client, e.MessageReaction) }
+		}
+	case *discordgo.MessageReactionRemove:
+		if l, ok := plugin.(ReactionRemoveListener); ok {
+			return func() { l.ReactionRemove(b, b.Client, e.MessageReaction) }
+		}
+	case *discordgo.GuildMemberAdd:
+		if l, ok := plugin.(MemberJoinListener); ok {
+			return func() { l.MemberJoin(b, b.Client, e.Member) }
+		}
+	case *discordgo.GuildMemberUpdate:
+		if l, ok := plugin.(MemberUpdateListener); ok {
+			return func() { l.MemberUpdate(b, b.Client, e.Member) }
+		}
+	case *discordgo.GuildMemberRemove:
+		if l, ok := plugin.(MemberLeaveListener); ok {
+			return func() { l.MemberLeave(b, b.Client, e.Member) }
+		}
+	case *discordgo.GuildCreate:
+		if l, ok := plugin.(GuildCreateListener); ok {
+			return func() { l.GuildCreate(b, b.Client, e.Guild) }
+		}
+	case *discordgo.GuildDelete:
+		if l, ok := plugin.(GuildDeleteListener); ok {
+			return func() { l.GuildDelete(b, b.Client, e.Guild) }
+		}
+	case *discordgo.ChannelCreate:
+		if l, ok := plugin.(ChannelCreateListener); ok {
+			return func() { l.ChannelCreate(b, b.Client, e.Channel) }
+		}
+	case *discordgo.ChannelDelete:
+		if l, ok := plugin.(ChannelDeleteListener); ok {
+			return func() { l.ChannelDelete(b, b.Client, e.Channel) }
+		}
+	case *discordgo.GuildRoleCreate:
+		if l, ok := plugin.(RoleCreateListener); ok {
+			return func() { l.RoleCreate(b, b.Client, e.GuildRole) }
+		}
+	case *discordgo.GuildRoleUpdate:
+		if l, ok := plugin.(RoleUpdateListener); ok {
+			return func() { l.RoleUpdate(b, b.Client, e.GuildRole) }
+		}
+	case *discordgo.GuildRoleDelete:
+		if l, ok := plugin.(RoleDeleteListener); ok {
+			return func() { l.RoleDelete(b, b.Client, e) }
+		}
+	}
+	return nil
+}
+
+// eventGuildID returns the guild an event happened in.
+func (b *Bot) eventGuildID(event interface{}) string {
+	switch e := event.(type) {
+	case *discordgo.MessageReactionAdd:
+		return b.Client.ChannelGuildID(e.ChannelID)
+	case *discordgo.MessageReactionRemove:
+		return b.Client.ChannelGuildID(e.ChannelID)
+	case *discordgo.GuildMemberAdd:
+		return e.GuildID
+	case *discordgo.GuildMemberUpdate:
+		return e.GuildID
+	case *discordgo.GuildMemberRemove:
+		return e.GuildID
+	case *discordgo.GuildCreate:
+		return e.ID
+	case *discordgo.GuildDelete:
+		return e.ID
+	case *discordgo.ChannelCreate:
+		return e.GuildID
+	case *discordgo.ChannelDelete:
+		return e.GuildID
+	case *discordgo.GuildRoleCreate:
+		return e.GuildID
+	case *discordgo.GuildRoleUpdate:
+		return e.GuildID
+	case *discordgo.GuildRoleDelete:
+		return e.GuildID
+	}
+	return ""
+}
@@ -6,18 +6,26 @@ import (
 	"log"
 	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
 )
 
+const helpPluginName = "Help"
+
+// helpPrivatePrefix is the prefix of the keys in the global storage bucket holding the per channel setting saved before help delivery moved into the guild configuration.
+// Entries are moved into the guild configuration once the guild of their channel becomes available.
+const helpPrivatePrefix = "private/"
+
 type helpPlugin struct {
-	Private map[string]bool
+	sync.Mutex
+	// Storage holds the per channel settings saved before they were kept in storage, by storage key, until Load puts them there.
+	Storage map[string]bool `json:",omitempty"`
 }
 
 func (p *helpPlugin) Name() string {
-	return "Help"
-}
-
-func (p *helpPlugin) Commands() []CommandDefinition {
-	return nil
+	return helpPluginName
 }
 
 // Help returns a list of help strings that are printed when the user requests them.
@@ -27,16 +35,22 @@ func (p *helpPlugin) Help(bot *Bot, client *Discord, message Message, detailed b
 		return nil
 	}
 
+	l := bot.Localizer(message)
+	config := client.messageGuildConfiguration(message)
+
 	commands := []string{}
 
 	for _, plugin := range bot.Plugins {
+		if bot.pluginDisabled(plugin, config) {
+			continue
+		}
+
 		hasDetailed := false
 
 		if plugin == p {
 			hasDetailed = privs
 		} else {
-			t := plugin.Help(bot, client, message, true)
-			hasDetailed = t != nil && len(t) > 0
+			hasDetailed = len(PluginCommands(plugin)) > 0 || len(PluginHelp(bot, client, message, plugin, true)) > 0
 		}
 
 		if hasDetailed {
@@ -49,13 +63,13 @@ func (p *helpPlugin) Help(bot *Bot, client *Discord, message Message, detailed b
 	help := []string{}
 
 	if len(commands) > 0 {
-		help = append(help, CommandHelp(client, "help", "[topic]", fmt.Sprintf("Returns help for a specific topic. Available topics: `%s`", strings.Join(commands, ", ")))[0])
+		help = append(help, ChannelCommandHelp(client, message.Channel(), "help", "[topic]", l.N("help.topics", len(commands), strings.Join(commands, ", ")))[0])
 	}
 
 	if detailed {
 		help = append(help, []string{
-			CommandHelp(client, "setprivatehelp", "", "Sets help text to be sent through private messages in this channel.")[0],
-			CommandHelp(client, "setpublichelp", "", "Sets the default help behavior for this channel.")[0],
+			ChannelCommandHelp(client, message.Channel(), "setprivatehelp", "", l.T("help.setprivatehelp"))[0],
+			ChannelCommandHelp(client, message.Channel(), "setpublichelp", "", l.T("help.setpublichelp"))[0],
 		}...)
 	}
 
@@ -67,91 +81,436 @@ func (p *helpPlugin) Message(bot *Bot, client *Discord, message Message) {
 		if MatchesCommand(client, "help", message) || MatchesCommand(client, "command", message) || MatchesCommand(client, "commands", message) {
 			_, parts := ParseCommand(client, message)
 
-			help := []string{}
+			l := bot.Localizer(message)
+			prefix := client.MessageCommandPrefix(message)
 
-			for _, plugin := range bot.Plugins {
-				var h []string
-				if len(parts) == 0 {
-					if plugin.Commands() == nil {
-						h = plugin.Help(bot, client, message, false)
-					} else {
-						for _, commandDefinition := range plugin.Commands() {
-							h = append(h, commandDefinition.Help(client))
-						}
-					}
-				} else if len(parts) == 1 && strings.ToLower(parts[0]) == strings.ToLower(plugin.Name()) {
-					if plugin.Commands() == nil {
-						h = plugin.Help(bot, client, message, true)
-					} else {
-						for _, commandDefinition := range plugin.Commands() {
-							h = append(h, commandDefinition.Help(client))
-						}
+			helpConfig := p.helpConfiguration(bot, client, message)
+
+			channel := message.Channel()
+			if helpConfig.Mode == HelpModePrivate {
+				privateChannel, err := client.PrivateChannelID(message.UserID())
+				if err != nil {
+					log.Println("Error creating private channel", err)
+					return
+				}
+				client.SendMessage(message.Channel(), l.T("help.sent-private"))
+				channel = privateChannel
+			}
+
+			send := func(pages []*discordgo.MessageEmbed) {
+				messageIDs := p.sendHelpPages(client, channel, message.UserID(), pages, l)
+				if helpConfig.Mode == HelpModeEphemeral {
+					deleteAfter := helpConfig.DeleteAfter
+					if deleteAfter <= 0 {
+						deleteAfter = defaultHelpDeleteAfter
 					}
+					client.DeleteMessagesAfter(channel, messageIDs, time.Duration(deleteAfter)*time.Second)
 				}
-				if h != nil && len(h) > 0 {
-					help = append(help, h...)
+			}
+
+			if len(parts) > 0 {
+				if embeds := p.commandHelpEmbeds(bot, client, message, parts[0], prefix, l); len(embeds) > 0 {
+					send(embeds)
+					return
 				}
 			}
 
-			if len(parts) == 0 {
-				sort.Strings(help)
-				help = append([]string{fmt.Sprintf("All commands can be used in private messages without the `%s` prefix.", client.CommandPrefix())}, help...)
+			groups := map[string][]string{}
+
+			for _, plugin := range bot.Plugins {
+				if len(parts) == 0 {
+					p.addPluginHelp(groups, bot, client, message, plugin, false, prefix, l)
+				} else if len(parts) == 1 && strings.ToLower(parts[0]) == strings.ToLower(plugin.Name()) {
+					p.addPluginHelp(groups, bot, client, message, plugin, true, prefix, l)
+				}
 			}
 
-			if len(parts) != 0 && len(help) == 0 {
-				help = []string{fmt.Sprintf("Unknown topic: %s", parts[0])}
+			if len(groups) == 0 {
+				if len(parts) != 0 {
+					client.SendMessage(channel, l.T("help.unknown-topic", parts[0]))
+				}
+				return
 			}
 
-			if p.Private[message.Channel()] {
-				client.SendMessage(message.Channel(), "Help has been sent via private message.")
-				client.PrivateMessage(message.UserID(), strings.Join(help, "\n"))
-			} else {
-				client.SendMessage(message.Channel(), strings.Join(help, "\n"))
+			description := ""
+			if len(parts) == 0 {
+				description = l.T("help.private-prefix", prefix)
 			}
+
+			send(helpPages(groups, description, l))
 		} else if MatchesCommand(client, "setprivatehelp", message) && !client.IsPrivate(message) {
 			if !client.IsModerator(message) {
 				return
 			}
 
-			p.Private[message.Channel()] = true
-
-			client.PrivateMessage(message.UserID(), fmt.Sprintf("Help text in <#%s> will be sent through private messages.", message.Channel()))
+			p.setChannelHelp(bot, client, message, &HelpConfiguration{Mode: HelpModePrivate}, "help.private-set")
 		} else if MatchesCommand(client, "setpublichelp", message) && !client.IsPrivate(message) {
 			if !client.IsModerator(message) {
 				return
 			}
 
-			p.Private[message.Channel()] = false
+			p.setChannelHelp(bot, client, message, &HelpConfiguration{Mode: HelpModePublic}, "help.public-set")
+		}
+	}
+}
+
+// helpConfiguration returns how help is delivered in the channel of a message.
+func (p *helpPlugin) helpConfiguration(bot *Bot, client *Discord, message Message) *HelpConfiguration {
+	config := client.messageGuildConfiguration(message)
+	if config == nil {
+		return &HelpConfiguration{Mode: HelpModePublic}
+	}
+	return config.helpConfiguration(message.Channel())
+}
+
+// GuildCreate moves the settings saved for the channels of a guild before help delivery moved into the guild configuration.
+// Which guild a channel belongs to is only known once the guild is available, so this is done here rather than when loading.
+func (p *helpPlugin) GuildCreate(bot *Bot, client *Discord, guild *discordgo.Guild) {
+	bucket := bot.Storage(p).Global()
 
-			client.PrivateMessage(message.UserID(), fmt.Sprintf("Help text in <#%s> will be sent publically.", message.Channel()))
+	keys := bucket.List(helpPrivatePrefix)
+	if len(keys) == 0 {
+		return
+	}
+
+	channels := map[string]bool{}
+	for _, channel := range guild.Channels {
+		channels[channel.ID] = true
+	}
+
+	var config *GuildConfiguration
+	moved := []string{}
+	for _, key := range keys {
+		channelID := strings.TrimPrefix(key, helpPrivatePrefix)
+		if !channels[channelID] {
+			continue
+		}
+
+		private := false
+		if _, err := bucket.GetJSON(key, &private); err != nil {
+			log.Println("Error reading help setting", err)
+			continue
+		}
+		moved = append(moved, key)
+
+		if !private {
+			continue
+		}
+		if config == nil {
+			// The settings stay in storage and are moved the next time the guild becomes available.
+			if config = bot.Core.editGuildConfiguration(guild.ID); config == nil {
+				return
+			}
+		}
+		// A setting made since the upgrade wins.
+		if _, ok := config.ChannelHelp[channelID]; !ok {
+			config.setChannelHelp(channelID, &HelpConfiguration{Mode: HelpModePrivate})
+		}
+	}
+
+	if config != nil {
+		if err := bot.Core.saveGuildConfiguration(config); err != nil {
+			log.Println("Error moving help settings", err)
+			return
 		}
 	}
+
+	for _, key := range moved {
+		bucket.Delete(key)
+	}
 }
 
-// Load will load plugin state from a byte array.
+// setChannelHelp stores how help is delivered in the channel of a message and lets the moderator know privately.
+func (p *helpPlugin) setChannelHelp(bot *Bot, client *Discord, message Message, help *HelpConfiguration, key string) {
+	l := bot.Localizer(message)
+
+	config := bot.Core.editGuildConfiguration(client.ChannelGuildID(message.Channel()))
+	if config == nil {
+		client.PrivateMessage(message.UserID(), l.T(InterProcessCommunicationFailure))
+		return
+	}
+
+	config.setChannelHelp(message.Channel(), help)
+	if err := bot.Core.saveGuildConfiguration(config); err != nil {
+		client.PrivateMessage(message.UserID(), l.T(err.Error()))
+		return
+	}
+
+	bot.Storage(p).Global().Delete(helpPrivatePrefix + message.Channel())
+
+	client.PrivateMessage(message.UserID(), l.T(key, message.Channel()))
+}
+
+// commandHelpEmbeds returns detailed help for every command of the plugin named by topic, or for every command triggered by it.
+// Commands the caller is not allowed to run are left out.
+func (p *helpPlugin) commandHelpEmbeds(bot *Bot, client *Discord, message Message, topic string, prefix string, l *Localizer) []*discordgo.MessageEmbed {
+	topic = strings.TrimPrefix(strings.ToLower(topic), strings.ToLower(prefix))
+
+	names := []string{}
+	for name := range bot.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	embeds := []*discordgo.MessageEmbed{}
+	config := client.messageGuildConfiguration(message)
+
+	for _, name := range names {
+		plugin := bot.Plugins[name]
+		if bot.pluginDisabled(plugin, config) {
+			continue
+		}
+
+		pluginMatch := strings.ToLower(plugin.Name()) == topic
+
+		for _, commandDefinition := range PluginCommands(plugin) {
+			matches := pluginMatch
+			for _, trigger := range commandDefinition.Triggers {
+				if strings.ToLower(trigger) == topic {
+					matches = true
+				}
+			}
+			if !matches || bot.commandDenied(plugin, &commandDefinition, message) != "" {
+				continue
+			}
+
+			embed := commandDefinition.HelpEmbed(prefix, l)
+			if entry := bot.maintenance(plugin, &commandDefinition); entry != nil {
+				embed.Footer = &discordgo.MessageEmbedFooter{
+					Text: entry.notice(l),
+				}
+			}
+			embeds = append(embeds, embed)
+		}
+	}
+
+	return embeds
+}
+
+// sendHelpPages sends help as a paginated embed, or as chunked text when the bot cannot post embeds in the channel.
+// It returns the IDs of the messages that were sent.
+func (p *helpPlugin) sendHelpPages(client *Discord, channel string, userID string, pages []*discordgo.MessageEmbed, l *Localizer) []string {
+	if !client.CanSendEmbed(channel) {
+		lines := []string{}
+		for _, page := range pages {
+			lines = append(lines, EmbedText(page)...)
+		}
+		messageIDs, _ := client.SendMessageChunks(channel, lines)
+		return messageIDs
+	}
+
+	if len(pages) > 1 {
+		for i, page := range pages {
+			text := l.T("help.page", i+1, len(pages))
+			if page.Footer != nil && page.Footer.Text != "" {
+				text = fmt.Sprintf("%s • %s", page.Footer.Text, text)
+			}
+			page.Footer = &discordgo.MessageEmbedFooter{
+				Text: text,
+			}
+		}
+	}
+
+	messageID, err := client.SendPaginatedEmbed(channel, userID, pages)
+	if err != nil || messageID == "" {
+		return nil
+	}
+	return []string{messageID}
+}
+
+// addPluginHelp adds the help lines for a plugin to their command group, marking anything the bot owner has disabled.
+// Plugins disabled in the guild and commands the caller is not allowed to run in this channel are left out.
+func (p *helpPlugin) addPluginHelp(groups map[string][]string, bot *Bot, client *Discord, message Message, plugin Plugin, detailed bool, prefix string, l *Localizer) {
+	if bot.pluginDisabled(plugin, client.messageGuildConfiguration(message)) {
+		return
+	}
+
+	commands := PluginCommands(plugin)
+	if commands == nil {
+		if plugin != p && bot.commandDenied(plugin, nil, message) != "" {
+			return
+		}
+
+		h := PluginHelp(bot, client, message, plugin, detailed)
+
+		// Legacy help formatted by CommandHelp has the default prefix.
+		if defaultPrefix := "`" + client.CommandPrefix(); prefix != client.CommandPrefix() {
+			for i := range h {
+				if strings.HasPrefix(h[i], defaultPrefix) && !strings.HasPrefix(h[i], "`"+prefix) {
+					h[i] = "`" + prefix + h[i][len(defaultPrefix):]
+				}
+			}
+		}
+
+		if entry := bot.maintenance(plugin, nil); entry != nil {
+			for i := range h {
+				h[i] = fmt.Sprintf("%s %s", h[i], l.T("maintenance.help-suffix"))
+			}
+		}
+		if len(h) > 0 {
+			groups[plugin.Name()] = append(groups[plugin.Name()], h...)
+		}
+		return
+	}
+
+	for _, commandDefinition := range commands {
+		if bot.commandDenied(plugin, &commandDefinition, message) != "" {
+			continue
+		}
+
+		line := commandDefinition.Help(prefix, l)
+		if entry := bot.maintenance(plugin, &commandDefinition); entry != nil {
+			line = fmt.Sprintf("%s %s", line, l.T("maintenance.help-suffix"))
+		}
+
+		group := commandDefinition.CommandGroup
+		if group == "" {
+			group = plugin.Name()
+		}
+		groups[group] = append(groups[group], line)
+	}
+}
+
+const (
+	helpFieldLength   = 1024
+	helpFieldsPerPage = 6
+	helpCharsPerPage  = 4000
+)
+
+// helpPages lays out help lines as embed pages with one field per command group.
+func helpPages(groups map[string][]string, description string, l *Localizer) []*discordgo.MessageEmbed {
+	names := []string{}
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pages := []*discordgo.MessageEmbed{}
+	var page *discordgo.MessageEmbed
+	pageLength := 0
+
+	for _, name := range names {
+		lines := groups[name]
+		sort.Strings(lines)
+
+		for _, value := range chunkLines(lines, helpFieldLength) {
+			if page == nil || len(page.Fields) >= helpFieldsPerPage || pageLength+len(value) > helpCharsPerPage {
+				page = &discordgo.MessageEmbed{
+					Title:       l.T("help.title"),
+					Description: description,
+					Color:       0x070707,
+				}
+				pages = append(pages, page)
+				pageLength = len(description)
+			}
+
+			page.Fields = append(page.Fields, &discordgo.MessageEmbedField{
+				Name:  name,
+				Value: value,
+			})
+			pageLength += len(name) + len(value)
+		}
+	}
+
+	return pages
+}
+
+// Migrations converts the data of older versions.
+// Version 0 kept the per channel settings in Private, version 1 keeps them in storage.
+func (p *helpPlugin) Migrations() []Migration {
+	return []Migration{migrateHelpToStorage}
+}
+
+// migrateHelpToStorage keys the settings of version 0 by their storage key, for Load to put them in storage.
+func migrateHelpToStorage(data json.RawMessage) (json.RawMessage, error) {
+	legacy := struct {
+		Private map[string]bool
+	}{}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, err
+	}
+
+	migrated := &helpPlugin{}
+	for channelID, private := range legacy.Private {
+		if migrated.Storage == nil {
+			migrated.Storage = make(map[string]bool)
+		}
+		migrated.Storage[helpPrivatePrefix+channelID] = private
+	}
+	return json.Marshal(migrated)
+}
+
+// Load will load plugin state from a byte array, putting settings saved by older versions in storage.
 func (p *helpPlugin) Load(bot *Bot, client *Discord, data []byte) error {
+	p.Lock()
+	defer p.Unlock()
+
 	if data != nil {
 		if err := json.Unmarshal(data, p); err != nil {
 			log.Println("Error loading data", err)
 		}
 	}
+
+	if len(p.Storage) == 0 {
+		return nil
+	}
+
+	bucket := bot.Storage(p).Global()
+	for key, private := range p.Storage {
+		if err := bucket.PutJSON(key, private); err != nil {
+			return err
+		}
+	}
+	p.Storage = nil
+	bot.MarkDirty(p)
+
 	return nil
 }
 
 // Save will save plugin state to a byte array.
 func (p *helpPlugin) Save() ([]byte, error) {
+	p.Lock()
+	defer p.Unlock()
+
 	return json.Marshal(p)
 }
 
-// Stats will return the stats for a plugin.
-func (p *helpPlugin) Stats(bot *Bot, client *Discord, message Message) []string {
+// GuildData returns the channels of a guild with a help setting that hasn't been moved into the guild configuration yet.
+func (p *helpPlugin) GuildData(bot *Bot, client *Discord, guildID string) (interface{}, error) {
+	bucket := bot.Storage(p).Global()
+
+	channels := map[string]bool{}
+	for _, key := range bucket.List(helpPrivatePrefix) {
+		channelID := strings.TrimPrefix(key, helpPrivatePrefix)
+		if client.ChannelGuildID(channelID) != guildID {
+			continue
+		}
+
+		private := false
+		if _, err := bucket.GetJSON(key, &private); err != nil {
+			return nil, err
+		}
+		channels[channelID] = private
+	}
+	if len(channels) == 0 {
+		return nil, nil
+	}
+	return channels, nil
+}
+
+// DeleteGuildData removes the help settings of the channels of a guild.
+func (p *helpPlugin) DeleteGuildData(bot *Bot, client *Discord, guildID string) error {
+	bucket := bot.Storage(p).Global()
+
+	for _, key := range bucket.List(helpPrivatePrefix) {
+		if client.ChannelGuildID(strings.TrimPrefix(key, helpPrivatePrefix)) == guildID {
+			bucket.Delete(key)
+		}
+	}
 	return nil
 }
 
 // NeHelpPlugin will create a new help plugin.
 func NewHelpPlugin() Plugin {
-	p := &helpPlugin{
-		Private: make(map[string]bool),
-	}
-	return p
+	return &helpPlugin{}
 }
@@ -3,6 +3,8 @@ package mutterblack
 import (
 	"errors"
 	"time"
+
+	"github.com/bwmarrin/discordgo"
 )
 
 // MessageType is a type used to determine the CRUD state of a message.
@@ -46,13 +48,125 @@ type MessageFunc func(*Bot, *Discord, Message)
 // StatsFunc is the function signature for a stats handler.
 type StatsFunc func(*Bot, *Discord, Message) []string
 
-// Plugin is a plugin interface, supports loading and saving to a byte array and has help and message handlers.
+// Plugin is the interface every plugin implements.
+// Everything else a plugin can do is an optional interface that the bot detects with a type assertion.
 type Plugin interface {
 	Name() string
+}
+
+// CommandProvider is implemented by plugins with commands that the bot matches and dispatches.
+type CommandProvider interface {
+	Commands() []CommandDefinition
+}
+
+// MessageListener is implemented by plugins that look at every message.
+type MessageListener interface {
+	Message(*Bot, *Discord, Message)
+}
+
+// MessageHandler is implemented by listeners that can stop a message from propagating, eg. an automod filter.
+// HandleMessage is called instead of Message, returning true keeps the message from later plugins and from every command.
+type MessageHandler interface {
+	HandleMessage(*Bot, *Discord, Message) bool
+}
+
+// PriorityProvider is implemented by plugins that need to see messages before or after other plugins.
+// Plugins with a higher priority see a message first, plugins without one have priority 0.
+// Only MessageHandlers are waited for, listeners are started in order and run alongside each other and commands.
+// Plugins with the same priority see messages in registration order.
+type PriorityProvider interface {
+	Priority() int
+}
+
+// StatsProvider is implemented by plugins that add lines to the stats command.
+type StatsProvider interface {
+	Stats(*Bot, *Discord, Message) []string
+}
+
+// Persister is implemented by plugins with state that is saved between runs.
+type Persister interface {
 	Load(*Bot, *Discord, []byte) error
 	Save() ([]byte, error)
+}
+
+// HelpProvider is implemented by plugins that write their own help instead of, or in addition to, describing commands.
+type HelpProvider interface {
 	Help(*Bot, *Discord, Message, bool) []string
-	Message(*Bot, *Discord, Message)
-	Stats(*Bot, *Discord, Message) []string
-	Commands() []CommandDefinition
+}
+
+// BasePlugin can be embedded to implement Name, so a plugin only has to implement the optional interfaces it needs.
+type BasePlugin struct {
+	name string
+}
+
+// NewBasePlugin returns a BasePlugin for a plugin called name.
+func NewBasePlugin(name string) BasePlugin {
+	return BasePlugin{name: name}
+}
+
+// Name returns the name of the plugin.
+func (p BasePlugin) Name() string {
+	return p.name
+}
+
+// PluginCommands returns the commands of a plugin, or nil if it has none.
+func PluginCommands(plugin Plugin) []CommandDefinition {
+	if provider, ok := plugin.(CommandProvider); ok {
+		return provider.Commands()
+	}
+	return nil
+}
+
+// PluginPriority returns the priority of a plugin, or 0 if it has none.
+func PluginPriority(plugin Plugin) int {
+	if provider, ok := plugin.(PriorityProvider); ok {
+		return provider.Priority()
+	}
+	return 0
+}
+
+// PluginHelp returns the help written by a plugin, or nil if it writes none.
+func PluginHelp(bot *Bot, client *Discord, message Message, plugin Plugin, detailed bool) []string {
+	if provider, ok := plugin.(HelpProvider); ok {
+		return provider.Help(bot, client, message, detailed)
+	}
+	return nil
+}
+
+// ReadyHandler is implemented by plugins that start background work once every shard is connected.
+// Ready is called after all plugins are loaded, in registration order.
+type ReadyHandler interface {
+	Ready(*Bot, *Discord) error
+}
+
+// ShutdownHandler is implemented by plugins that need to stop background work before the bot exits.
+// Shutdown is called by Bot.Close in reverse registration order, before plugins are saved.
+type ShutdownHandler interface {
+	Shutdown(*Bot, *Discord) error
+}
+
+// GuildJoinHandler is implemented by plugins that want to know when the bot is added to a guild.
+// Guilds the bot was already in when it connected are not reported.
+type GuildJoinHandler interface {
+	GuildJoin(*Bot, *Discord, *discordgo.Guild) error
+}
+
+// GuildLeaveHandler is implemented by plugins that want to know when the bot is removed from a guild.
+// Guilds that become unavailable during an outage are not reported.
+type GuildLeaveHandler interface {
+	GuildLeave(*Bot, *Discord, string) error
+}
+
+// GuildDataProvider is implemented by plugins that keep data about guilds outside their Storage, so guild owners can export and purge it.
+// GuildData returns a value that is exported as JSON, or nil if the plugin has nothing for the guild.
+type GuildDataProvider interface {
+	GuildData(*Bot, *Discord, string) (interface{}, error)
+	DeleteGuildData(*Bot, *Discord, string) error
+}
+
+// UserDataProvider is implemented by plugins that keep data about users outside their Storage, so users can export and delete it.
+// UserData returns a value that is exported as JSON, or nil if the plugin has nothing for the user.
+type UserDataProvider interface {
+	UserData(*Bot, *Discord, string) (interface{}, error)
+	DeleteUserData(*Bot, *Discord, string) error
 }
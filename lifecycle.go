@@ -0,0 +1,165 @@
+package mutterblack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// lifecycleError collects the errors returned by plugins for a lifecycle hook.
+type lifecycleError struct {
+	hook   string
+	errors []string
+}
+
+func (e *lifecycleError) Error() string {
+	return fmt.Sprintf("%s failed: %s", e.hook, strings.Join(e.errors, "; "))
+}
+
+// orderedPlugins returns the registered plugins in registration order, or in reverse when reverse is set.
+func (b *Bot) orderedPlugins(reverse bool) []Plugin {
+	plugins := []Plugin{}
+	for _, name := range b.pluginOrder {
+		if plugin, ok := b.Plugins[name]; ok {
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	if reverse {
+		for i, j := 0, len(plugins)-1; i < j; i, j = i+1, j-1 {
+			plugins[i], plugins[j] = plugins[j], plugins[i]
+		}
+	}
+
+	return plugins
+}
+
+// runLifecycle calls a lifecycle hook on every plugin, a failing or panicking plugin does not stop the others.
+// Every failure is logged and the failures are returned together.
+func (b *Bot) runLifecycle(hook string, reverse bool, call func(plugin Plugin) error) error {
+	lifecycleErr := &lifecycleError{hook: hook}
+
+	for _, plugin := range b.orderedPlugins(reverse) {
+		if err := b.callLifecycle(plugin, hook, call); err != nil {
+			log.Printf("Error running %s for plugin %s: %v", hook, plugin.Name(), err)
+			lifecycleErr.errors = append(lifecycleErr.errors, fmt.Sprintf("%s: %v", plugin.Name(), err))
+		}
+	}
+
+	if len(lifecycleErr.errors) > 0 {
+		return lifecycleErr
+	}
+	return nil
+}
+
+func (b *Bot) callLifecycle(plugin Plugin, hook string, call func(plugin Plugin) error) error {
+	var err error
+	if b.protect(plugin, hook, "", func() { err = call(plugin) }) {
+		return fmt.Errorf("panicked")
+	}
+	return err
+}
+
+func (b *Bot) onReady(s *discordgo.Session, r *discordgo.Ready) {
+	b.lifecycleLock.Lock()
+
+	// Every Ready lists the guilds of the shard, including the one sent after reconnecting, which is followed by a GuildCreate for each of them.
+	for _, guild := range r.Guilds {
+		b.knownGuilds[guild.ID] = true
+	}
+
+	if b.readyShards[s.ShardID] {
+		b.lifecycleLock.Unlock()
+		return
+	}
+	b.readyShards[s.ShardID] = true
+
+	if len(b.readyShards) == len(b.Client.Sessions) {
+		close(b.ready)
+	}
+
+	// Guilds whose GuildCreate was handled first are only new if this Ready didn't list them.
+	joined := []*discordgo.Guild{}
+	for _, guild := range b.pendingGuilds[s.ShardID] {
+		if !b.knownGuilds[guild.ID] {
+			b.knownGuilds[guild.ID] = true
+			joined = append(joined, guild)
+		}
+	}
+	delete(b.pendingGuilds, s.ShardID)
+
+	b.lifecycleLock.Unlock()
+
+	for _, guild := range joined {
+		b.guildJoin(guild)
+	}
+}
+
+func (b *Bot) onGuildCreate(s *discordgo.Session, g *discordgo.GuildCreate) {
+	if g.Unavailable {
+		return
+	}
+
+	b.lifecycleLock.Lock()
+	if !b.readyShards[s.ShardID] {
+		b.pendingGuilds[s.ShardID] = append(b.pendingGuilds[s.ShardID], g.Guild)
+		b.lifecycleLock.Unlock()
+		return
+	}
+	known := b.knownGuilds[g.ID]
+	b.knownGuilds[g.ID] = true
+	b.lifecycleLock.Unlock()
+
+	if !known {
+		b.guildJoin(g.Guild)
+	}
+}
+
+func (b *Bot) guildJoin(guild *discordgo.Guild) {
+	b.runLifecycle("GuildJoin", false, func(plugin Plugin) error {
+		if handler, ok := plugin.(GuildJoinHandler); ok {
+			return handler.GuildJoin(b, b.Client, guild)
+		}
+		return nil
+	})
+}
+
+func (b *Bot) onGuildDelete(s *discordgo.Session, g *discordgo.GuildDelete) {
+	if g.Unavailable {
+		return
+	}
+
+	b.lifecycleLock.Lock()
+	delete(b.knownGuilds, g.ID)
+	if pending, ok := b.pendingGuilds[s.ShardID]; ok {
+		remaining := []*discordgo.Guild{}
+		for _, guild := range pending {
+			if guild.ID != g.ID {
+				remaining = append(remaining, guild)
+			}
+		}
+		b.pendingGuilds[s.ShardID] = remaining
+	}
+	b.lifecycleLock.Unlock()
+
+	b.runLifecycle("GuildLeave", false, func(plugin Plugin) error {
+		if handler, ok := plugin.(GuildLeaveHandler); ok {
+			return handler.GuildLeave(b, b.Client, g.ID)
+		}
+		return nil
+	})
+}
+
+// waitReady calls Ready on every plugin once all shards are connected.
+func (b *Bot) waitReady() {
+	<-b.ready
+
+	b.runLifecycle("Ready", false, func(plugin Plugin) error {
+		if handler, ok := plugin.(ReadyHandler); ok {
+			return handler.Ready(b, b.Client)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,221 @@
+package mutterblack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale used when neither the user nor the guild has chosen one.
+const DefaultLocale = "en"
+
+// PluralForm is a CLDR plural category.
+type PluralForm string
+
+const (
+	// PluralZero is the plural form for zero items in locales that distinguish it.
+	PluralZero PluralForm = "zero"
+	// PluralOne is the plural form for a single item.
+	PluralOne = "one"
+	// PluralTwo is the plural form for two items in locales that distinguish it.
+	PluralTwo = "two"
+	// PluralFew is the plural form for small quantities in locales that distinguish it.
+	PluralFew = "few"
+	// PluralMany is the plural form for large quantities in locales that distinguish it.
+	PluralMany = "many"
+	// PluralOther is the fallback plural form.
+	PluralOther = "other"
+)
+
+// PluralRule selects the plural form for a count.
+type PluralRule func(count int) PluralForm
+
+// Catalog maps message keys to translated format strings.
+// Plural messages are stored with the plural form appended to the key, eg. "stats.shards#one".
+type Catalog map[string]string
+
+type localeRegistry struct {
+	sync.RWMutex
+	catalogs map[string]Catalog
+	names    map[string]string
+	rules    map[string]PluralRule
+}
+
+var locales = &localeRegistry{
+	catalogs: make(map[string]Catalog),
+	names:    make(map[string]string),
+	rules:    make(map[string]PluralRule),
+}
+
+func oneOtherPluralRule(count int) PluralForm {
+	if count == 1 || count == -1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// RegisterLocale registers a locale with its display name and plural rule.
+// Locales without a registered rule use the English one/other rule.
+func RegisterLocale(locale string, name string, rule PluralRule) {
+	locale = normalizeLocale(locale)
+
+	locales.Lock()
+	defer locales.Unlock()
+
+	locales.names[locale] = name
+	if rule != nil {
+		locales.rules[locale] = rule
+	}
+	if locales.catalogs[locale] == nil {
+		locales.catalogs[locale] = make(Catalog)
+	}
+}
+
+// RegisterCatalog merges catalog into the messages for a locale.
+// Plugins call this from init to ship translations for their own keys.
+func RegisterCatalog(locale string, catalog Catalog) {
+	locale = normalizeLocale(locale)
+
+	locales.Lock()
+	defer locales.Unlock()
+
+	existing := locales.catalogs[locale]
+	if existing == nil {
+		existing = make(Catalog)
+		locales.catalogs[locale] = existing
+	}
+	for key, message := range catalog {
+		existing[key] = message
+	}
+}
+
+// SupportedLocales returns the sorted list of locales that have a registered name.
+func SupportedLocales() []string {
+	locales.RLock()
+	defer locales.RUnlock()
+
+	supported := make([]string, 0, len(locales.names))
+	for locale := range locales.names {
+		supported = append(supported, locale)
+	}
+	sort.Strings(supported)
+	return supported
+}
+
+// LocaleName returns the display name of a locale, or the locale itself if it is unknown.
+func LocaleName(locale string) string {
+	locales.RLock()
+	defer locales.RUnlock()
+
+	if name, ok := locales.names[normalizeLocale(locale)]; ok {
+		return name
+	}
+	return locale
+}
+
+// MatchLocale returns the supported locale closest to the requested one, falling back from a region to its base language.
+func MatchLocale(locale string) (string, bool) {
+	locale = normalizeLocale(locale)
+
+	locales.RLock()
+	defer locales.RUnlock()
+
+	if _, ok := locales.names[locale]; ok {
+		return locale, true
+	}
+	if i := strings.Index(locale, "-"); i > 0 {
+		if _, ok := locales.names[locale[:i]]; ok {
+			return locale[:i], true
+		}
+	}
+	return "", false
+}
+
+func normalizeLocale(locale string) string {
+	return strings.Replace(strings.ToLower(strings.TrimSpace(locale)), "_", "-", -1)
+}
+
+// Localizer translates catalog messages into a single locale.
+type Localizer struct {
+	Locale string
+}
+
+// NewLocalizer creates a localizer for the closest supported match of locale.
+func NewLocalizer(locale string) *Localizer {
+	if matched, ok := MatchLocale(locale); ok {
+		return &Localizer{Locale: matched}
+	}
+	return &Localizer{Locale: DefaultLocale}
+}
+
+func (l *Localizer) lookup(key string) (string, bool) {
+	locales.RLock()
+	defer locales.RUnlock()
+
+	if message, ok := locales.catalogs[l.Locale][key]; ok {
+		return message, true
+	}
+	if message, ok := locales.catalogs[DefaultLocale][key]; ok {
+		return message, true
+	}
+	return "", false
+}
+
+func (l *Localizer) pluralForm(count int) PluralForm {
+	locales.RLock()
+	defer locales.RUnlock()
+
+	if rule, ok := locales.rules[l.Locale]; ok {
+		return rule(count)
+	}
+	return oneOtherPluralRule(count)
+}
+
+// T returns the message for key formatted with args.
+// Keys that are not in any catalog are returned unchanged, so untranslated text and errors from the core still read correctly.
+func (l *Localizer) T(key string, args ...interface{}) string {
+	message, ok := l.lookup(key)
+	if !ok {
+		message = key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// N returns the plural form of key selected by count, formatted with args.
+func (l *Localizer) N(key string, count int, args ...interface{}) string {
+	message, ok := l.lookup(fmt.Sprintf("%s#%s", key, l.pluralForm(count)))
+	if !ok {
+		message, ok = l.lookup(fmt.Sprintf("%s#%s", key, PluralOther))
+	}
+	if !ok {
+		return l.T(key, args...)
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Localizer returns a localizer for the author of a message.
+// A user's own preference wins over their guild's locale, which wins over DefaultLocale.
+func (b *Bot) Localizer(message Message) *Localizer {
+	if message == nil {
+		return NewLocalizer(DefaultLocale)
+	}
+
+	if p, ok := b.Plugins[localePluginName].(*localePlugin); ok {
+		if locale := p.userLocale(message.UserID()); locale != "" {
+			return NewLocalizer(locale)
+		}
+	}
+
+	if config := b.Client.messageGuildConfiguration(message); config != nil && config.Locale != "" {
+		return NewLocalizer(config.Locale)
+	}
+
+	return NewLocalizer(DefaultLocale)
+}
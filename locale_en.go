@@ -0,0 +1,192 @@
+package mutterblack
+
+func init() {
+	RegisterLocale("en", "English", oneOtherPluralRule)
+	RegisterCatalog("en", Catalog{
+		"error.core-communication":  "Failed to communicate with core routines",
+		"error.unexpected-response": "Something went wrong :(",
+		"error.core-unavailable":    "That is temporarily unavailable, please try again in a few minutes.",
+		"error.moderator-only":      "Only server moderators can use that command.",
+		"error.owner-only":          "Only the bot owner can use that command.",
+		"error.missing-permissions": "You do not have permission to use that command.",
+		"error.cooldown":            "Please wait %s before using that command again.",
+		"error.command-disabled":    "That command is disabled in this server.",
+		"error.channel-restricted":  "That command can only be used in %s.",
+		"error.role-restricted":     "You do not have a role that can use that command.",
+
+		"help.topics#one":     "Returns help for a specific topic. Available topic: `%s`",
+		"help.topics#other":   "Returns help for a specific topic. Available topics: `%s`",
+		"help.setprivatehelp": "Sets help text to be sent through private messages in this channel.",
+		"help.setpublichelp":  "Sets the default help behavior for this channel.",
+		"help.private-prefix": "All commands can be used in private messages without the `%s` prefix.",
+		"help.unknown-topic":  "Unknown topic: %s",
+		"help.sent-private":   "Help has been sent via private message.",
+		"help.private-set":    "Help text in <#%s> will be sent through private messages.",
+		"help.public-set":     "Help text in <#%s> will be sent publically.",
+		"help.title":          "Commands",
+		"help.page":           "Page %d/%d",
+
+		"locale.command.user-set":  "Sets the language the bot uses when replying to you. Use `default` to follow the server language.",
+		"locale.command.user-show": "Shows your current language and the available languages.",
+		"locale.command.guild-set": "Sets the default language for this server. Use `default` to reset it.",
+		"locale.current":           "Your current language is %s.",
+		"locale.available#one":     "Available language: %s",
+		"locale.available#other":   "Available languages: %s",
+		"locale.unknown":           "Unknown language `%s`. Available languages: %s",
+		"locale.user-set":          "I will now reply to you in %s.",
+		"locale.user-reset":        "Your language preference has been cleared, I will reply to you in %s.",
+		"locale.guild-set":         "The default language for this server is now %s.",
+
+		"maintenance.command.disable": "Disables a plugin or command ID for every server, with an optional message shown to users.",
+		"maintenance.command.enable":  "Enables a plugin or command ID that was disabled.",
+		"maintenance.command.list":    "Lists the plugins and commands that are currently disabled.",
+		"maintenance.unknown-target":  "There is no plugin or command ID named `%s`.",
+		"maintenance.cannot-disable":  "The maintenance commands cannot be disabled.",
+		"maintenance.disabled":        "`%s` has been disabled.",
+		"maintenance.enabled":         "`%s` has been enabled.",
+		"maintenance.none":            "Nothing is disabled.",
+		"maintenance.list-plugin":     "Plugin `%s` disabled %s %s",
+		"maintenance.list-command":    "Command `%s` disabled %s %s",
+		"maintenance.notice":          "This command is temporarily unavailable.",
+		"maintenance.notice-message":  "This command is temporarily unavailable: %s",
+		"maintenance.help-suffix":     "*(temporarily unavailable)*",
+
+		"data.command.guild-export": "Sends you a file with everything the bot holds about this server. Server owner only.",
+		"data.command.guild-purge":  "Deletes everything the bot holds about this server. Server owner only.",
+		"data.command.user-export":  "Sends you a file with everything the bot holds about you.",
+		"data.command.user-delete":  "Deletes everything the bot holds about you.",
+		"data.guild-only":           "That command can only be used in a server.",
+		"data.guild-owner-only":     "Only the server owner can use that command.",
+		"data.guild-purge-confirm":  "This permanently deletes the configuration of this server and everything plugins hold about it and its members. Use `%s %s` to continue.",
+		"data.user-delete-confirm":  "This permanently deletes everything the bot holds about you in every server. Use `%s %s` to continue.",
+		"data.guild-purged":         "Everything the bot held about this server has been deleted, including its backups. Messages the bot already sent and its logs are not affected.",
+		"data.user-deleted":         "Everything the bot held about that user has been deleted, including its backups. Messages the bot already sent and its logs are not affected.",
+		"data.export-sent":          "The export has been sent via private message.",
+		"data.export-failed":        "The data couldn't be exported, please try again later.",
+		"data.export-incomplete":    "The export has been sent via private message, but some of the data couldn't be loaded. Its `errors` list what is missing.",
+		"data.purge-failed":         "Not all of the data could be deleted, please try again later. Everything else has been deleted.",
+
+		"help.field.aliases#one":     "Alias",
+		"help.field.aliases#other":   "Aliases",
+		"help.field.arguments#one":   "Argument",
+		"help.field.arguments#other": "Arguments",
+		"help.field.examples#one":    "Example",
+		"help.field.examples#other":  "Examples",
+		"help.field.permissions":     "Required permissions",
+		"help.field.cooldown":        "Cooldown",
+		"help.argument.required":     "required",
+		"help.argument.optional":     "optional",
+		"help.argument-type.text":    "text",
+		"help.argument-type.word":    "word",
+		"help.argument-type.number":  "number",
+		"help.argument-type.channel": "channel",
+		"help.argument-type.role":    "role",
+
+		"panic.report#one":      "**Panic** in `%s` `%s`, %d new occurrence (%d total) at `%s`",
+		"panic.report#other":    "**Panic** in `%s` `%s`, %d new occurrences (%d total) at `%s`",
+		"panic.value":           "Error: %s",
+		"panic.input":           "Input: `%s`",
+		"storage.backup-loaded": "**Storage** `%s` couldn't be loaded (%v), the backup saved %s was loaded instead.",
+		"storage.load-failed":   "**Storage** `%s` couldn't be loaded (%v) and no backup could be loaded either.",
+
+		"core.stale-footer#one":   "Cached data from %d minute ago, refreshing",
+		"core.stale-footer#other": "Cached data from %d minutes ago, refreshing",
+
+		"permission.bot-owner":            "Bot owner",
+		"permission.moderator":            "Server moderator",
+		"permission.administrator":        "Administrator",
+		"permission.manage-server":        "Manage Server",
+		"permission.manage-channels":      "Manage Channels",
+		"permission.manage-roles":         "Manage Roles",
+		"permission.manage-messages":      "Manage Messages",
+		"permission.kick-members":         "Kick Members",
+		"permission.ban-members":          "Ban Members",
+		"permission.embed-links":          "Embed Links",
+		"permission.attach-files":         "Attach Files",
+		"permission.read-message-history": "Read Message History",
+
+		"locale.argument.locale":       "A language code such as `en` or `es`, or `default`.",
+		"maintenance.argument.target":  "A plugin name or command ID.",
+		"maintenance.argument.message": "A message shown to users who try to use it.",
+		"data.argument.confirm":        "`confirm` to go ahead.",
+		"data.argument.user":           "A user mention or ID, only the bot owner can name someone else.",
+
+		"configure.command.prefix":                 "Sets the command prefix for this server.",
+		"configure.command.channel-set":            "Restricts all commands to a channel, can be used for several channels.",
+		"configure.command.channel-remove":         "Removes a channel from the server wide channel restriction.",
+		"configure.command.channel-list":           "Lists the channels commands are restricted to.",
+		"configure.command.role-set":               "Restricts all commands to members with a role, can be used for several roles.",
+		"configure.command.role-remove":            "Removes a role from the server wide role restriction.",
+		"configure.command.role-list":              "Lists the roles commands are restricted to.",
+		"configure.command.command-enable":         "Enables a command in this server.",
+		"configure.command.command-disable":        "Disables a command in this server.",
+		"configure.command.command-channel-set":    "Restricts a command to a channel, can be used for several channels.",
+		"configure.command.command-channel-remove": "Removes a channel from a command's channel restriction.",
+		"configure.command.command-channel-list":   "Lists the channels a command is restricted to.",
+		"configure.command.command-role-set":       "Restricts a command to members with a role, can be used for several roles.",
+		"configure.command.command-role-remove":    "Removes a role from a command's role restriction.",
+		"configure.command.command-role-list":      "Lists the roles a command is restricted to.",
+		"configure.command.help":                   "Sets how help is delivered in this server: `public`, `private` messages or `ephemeral` messages deleted after some seconds.",
+		"configure.command.channel-help":           "Sets how help is delivered in a channel, overriding the server setting.",
+		"configure.command.channel-help-remove":    "Makes a channel use the server help setting again.",
+		"configure.command.help-show":              "Shows how help is delivered in this server and its channels.",
+		"configure.argument.help-mode":             "`public`, `private` or `ephemeral`.",
+		"configure.argument.seconds":               "Seconds before ephemeral help is deleted, defaults to 60.",
+		"configure.help-set":                       "Help in this server is now %s.",
+		"configure.channel-help-set":               "Help in <#%s> is now %s.",
+		"configure.channel-help-removed":           "<#%s> now uses the server help setting, %s.",
+		"configure.all-channels":                   "All channels",
+		"configure.help-mode.public":               "posted in the channel",
+		"configure.help-mode.private":              "sent through private messages",
+		"configure.help-mode.ephemeral#one":        "posted in the channel and deleted after %d second",
+		"configure.help-mode.ephemeral#other":      "posted in the channel and deleted after %d seconds",
+		"configure.command.plugin-enable":          "Enables a plugin in this server.",
+		"configure.command.plugin-disable":         "Disables a plugin in this server, it won't see messages or show up in help.",
+		"configure.command.plugin-only":            "Only allows the listed plugins in this server, use `all` to allow every plugin again.",
+		"configure.command.plugin-list":            "Lists the plugins and whether they are enabled in this server.",
+		"configure.argument.plugin":                "A plugin name, as listed by `listPlugins`.",
+		"configure.argument.plugins":               "Plugin names separated by spaces, or `all`.",
+		"configure.unknown-plugin":                 "Unknown plugin: %s",
+		"configure.plugin-protected":               "%s can't be disabled.",
+		"configure.plugin-enabled":                 "Enabled %s in this server.",
+		"configure.plugin-disabled":                "Disabled %s in this server.",
+		"configure.plugins-only":                   "Only %s are allowed in this server now.",
+		"configure.plugins-all":                    "All plugins are allowed in this server now.",
+		"configure.plugin-state.enabled":           "enabled",
+		"configure.plugin-state.disabled":          "disabled",
+		"configure.plugin-state.protected":         "always enabled",
+		"configure.command.setting-set":            "Changes a plugin setting in this server.",
+		"configure.command.setting-reset":          "Sets a plugin setting back to its default in this server.",
+		"configure.command.setting-show":           "Shows the settings of a plugin in this server.",
+		"configure.argument.settings-plugin":       "A plugin with settings, as listed by `listPlugins`.",
+		"configure.argument.key":                   "A setting, as listed by `show`.",
+		"configure.argument.value":                 "The new value of the setting.",
+		"configure.no-settings":                    "%s has no settings.",
+		"configure.unknown-setting":                "%s has no setting called `%s`.",
+		"configure.setting-set":                    "%s setting `%s` is now %s.",
+		"configure.setting-reset":                  "%s setting `%s` is back to its default, %s.",
+		"configure.setting.default":                "%s (default)",
+		"configure.setting.unset":                  "not set",
+		"configure.setting.on":                     "on",
+		"configure.setting.off":                    "off",
+		"configure.setting.invalid-number":         "The value must be a whole number.",
+		"configure.setting.invalid-bool":           "The value must be `on` or `off`.",
+		"configure.setting.invalid-choice":         "The value must be one of: %s",
+		"configure.argument.prefix":                "The new prefix, up to 5 characters.",
+		"configure.argument.channel":               "A channel mention or name.",
+		"configure.argument.role":                  "A role mention or name.",
+		"configure.argument.command":               "A command trigger or command ID.",
+		"configure.unknown-command":                "Unknown command: %s",
+		"configure.unknown-channel":                "Unknown channel: %s",
+		"configure.unknown-role":                   "Unknown role: %s",
+		"configure.prefix-set":                     "The command prefix for this server is now `%s`.",
+		"configure.channel-added":                  "<#%s> added to the allowed channels for %s.",
+		"configure.channel-removed":                "<#%s> removed from the allowed channels for %s.",
+		"configure.role-added":                     "@%s added to the allowed roles for %s.",
+		"configure.role-removed":                   "@%s removed from the allowed roles for %s.",
+		"configure.command-enabled":                "Enabled %s.",
+		"configure.command-disabled":               "Disabled %s.",
+		"configure.unrestricted":                   "unrestricted",
+		"configure.all-commands":                   "all commands",
+	})
+}
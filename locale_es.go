@@ -0,0 +1,192 @@
+package mutterblack
+
+func init() {
+	RegisterLocale("es", "Español", oneOtherPluralRule)
+	RegisterCatalog("es", Catalog{
+		"error.core-communication":  "No se pudo comunicar con los servicios principales",
+		"error.unexpected-response": "Algo salió mal :(",
+		"error.core-unavailable":    "No está disponible temporalmente, inténtalo de nuevo en unos minutos.",
+		"error.moderator-only":      "Solo los moderadores del servidor pueden usar ese comando.",
+		"error.owner-only":          "Solo el propietario del bot puede usar ese comando.",
+		"error.missing-permissions": "No tienes permiso para usar ese comando.",
+		"error.cooldown":            "Espera %s antes de volver a usar ese comando.",
+		"error.command-disabled":    "Ese comando está desactivado en este servidor.",
+		"error.channel-restricted":  "Ese comando solo se puede usar en %s.",
+		"error.role-restricted":     "No tienes un rol que pueda usar ese comando.",
+
+		"help.topics#one":     "Muestra la ayuda de un tema específico. Tema disponible: `%s`",
+		"help.topics#other":   "Muestra la ayuda de un tema específico. Temas disponibles: `%s`",
+		"help.setprivatehelp": "Envía el texto de ayuda de este canal por mensaje privado.",
+		"help.setpublichelp":  "Restablece el comportamiento predeterminado de la ayuda en este canal.",
+		"help.private-prefix": "Todos los comandos se pueden usar en mensajes privados sin el prefijo `%s`.",
+		"help.unknown-topic":  "Tema desconocido: %s",
+		"help.sent-private":   "La ayuda se ha enviado por mensaje privado.",
+		"help.private-set":    "El texto de ayuda en <#%s> se enviará por mensaje privado.",
+		"help.public-set":     "El texto de ayuda en <#%s> se enviará públicamente.",
+		"help.title":          "Comandos",
+		"help.page":           "Página %d/%d",
+
+		"locale.command.user-set":  "Establece el idioma en el que el bot te responde. Usa `default` para seguir el idioma del servidor.",
+		"locale.command.user-show": "Muestra tu idioma actual y los idiomas disponibles.",
+		"locale.command.guild-set": "Establece el idioma predeterminado de este servidor. Usa `default` para restablecerlo.",
+		"locale.current":           "Tu idioma actual es %s.",
+		"locale.available#one":     "Idioma disponible: %s",
+		"locale.available#other":   "Idiomas disponibles: %s",
+		"locale.unknown":           "Idioma desconocido `%s`. Idiomas disponibles: %s",
+		"locale.user-set":          "A partir de ahora te responderé en %s.",
+		"locale.user-reset":        "Se ha borrado tu preferencia de idioma, te responderé en %s.",
+		"locale.guild-set":         "El idioma predeterminado de este servidor ahora es %s.",
+
+		"maintenance.command.disable": "Desactiva un plugin o ID de comando en todos los servidores, con un mensaje opcional para los usuarios.",
+		"maintenance.command.enable":  "Vuelve a activar un plugin o ID de comando desactivado.",
+		"maintenance.command.list":    "Muestra los plugins y comandos que están desactivados.",
+		"maintenance.unknown-target":  "No existe ningún plugin ni ID de comando llamado `%s`.",
+		"maintenance.cannot-disable":  "Los comandos de mantenimiento no se pueden desactivar.",
+		"maintenance.disabled":        "`%s` se ha desactivado.",
+		"maintenance.enabled":         "`%s` se ha activado.",
+		"maintenance.none":            "No hay nada desactivado.",
+		"maintenance.list-plugin":     "Plugin `%s` desactivado %s %s",
+		"maintenance.list-command":    "Comando `%s` desactivado %s %s",
+		"maintenance.notice":          "Este comando no está disponible temporalmente.",
+		"maintenance.notice-message":  "Este comando no está disponible temporalmente: %s",
+		"maintenance.help-suffix":     "*(no disponible temporalmente)*",
+
+		"data.command.guild-export": "Te envía un archivo con todo lo que el bot guarda sobre este servidor. Solo para el dueño del servidor.",
+		"data.command.guild-purge":  "Borra todo lo que el bot guarda sobre este servidor. Solo para el dueño del servidor.",
+		"data.command.user-export":  "Te envía un archivo con todo lo que el bot guarda sobre ti.",
+		"data.command.user-delete":  "Borra todo lo que el bot guarda sobre ti.",
+		"data.guild-only":           "Ese comando solo se puede usar en un servidor.",
+		"data.guild-owner-only":     "Solo el dueño del servidor puede usar ese comando.",
+		"data.guild-purge-confirm":  "Esto borra para siempre la configuración de este servidor y todo lo que los plugins guardan sobre él y sus miembros. Usa `%s %s` para continuar.",
+		"data.user-delete-confirm":  "Esto borra para siempre todo lo que el bot guarda sobre ti en todos los servidores. Usa `%s %s` para continuar.",
+		"data.guild-purged":         "Se ha borrado todo lo que el bot guardaba sobre este servidor, incluidas sus copias de seguridad. Los mensajes que el bot ya envió y sus registros no se ven afectados.",
+		"data.user-deleted":         "Se ha borrado todo lo que el bot guardaba sobre ese usuario, incluidas sus copias de seguridad. Los mensajes que el bot ya envió y sus registros no se ven afectados.",
+		"data.export-sent":          "La exportación se ha enviado por mensaje privado.",
+		"data.export-failed":        "No se pudieron exportar los datos, inténtalo de nuevo más tarde.",
+		"data.export-incomplete":    "La exportación se ha enviado por mensaje privado, pero no se pudieron cargar algunos datos. Su lista `errors` indica lo que falta.",
+		"data.purge-failed":         "No se pudieron borrar todos los datos, inténtalo de nuevo más tarde. Todo lo demás se ha borrado.",
+
+		"help.field.aliases#one":     "Alias",
+		"help.field.aliases#other":   "Alias",
+		"help.field.arguments#one":   "Argumento",
+		"help.field.arguments#other": "Argumentos",
+		"help.field.examples#one":    "Ejemplo",
+		"help.field.examples#other":  "Ejemplos",
+		"help.field.permissions":     "Permisos necesarios",
+		"help.field.cooldown":        "Tiempo de espera",
+		"help.argument.required":     "obligatorio",
+		"help.argument.optional":     "opcional",
+		"help.argument-type.text":    "texto",
+		"help.argument-type.word":    "palabra",
+		"help.argument-type.number":  "número",
+		"help.argument-type.channel": "canal",
+		"help.argument-type.role":    "rol",
+
+		"panic.report#one":      "**Pánico** en `%s` `%s`, %d nueva ocurrencia (%d en total) en `%s`",
+		"panic.report#other":    "**Pánico** en `%s` `%s`, %d nuevas ocurrencias (%d en total) en `%s`",
+		"panic.value":           "Error: %s",
+		"panic.input":           "Entrada: `%s`",
+		"storage.backup-loaded": "**Almacenamiento** No se pudo cargar `%s` (%v), se cargó en su lugar la copia de seguridad guardada el %s.",
+		"storage.load-failed":   "**Almacenamiento** No se pudo cargar `%s` (%v) ni ninguna copia de seguridad.",
+
+		"core.stale-footer#one":   "Datos guardados hace %d minuto, actualizando",
+		"core.stale-footer#other": "Datos guardados hace %d minutos, actualizando",
+
+		"permission.bot-owner":            "Propietario del bot",
+		"permission.moderator":            "Moderador del servidor",
+		"permission.administrator":        "Administrador",
+		"permission.manage-server":        "Gestionar servidor",
+		"permission.manage-channels":      "Gestionar canales",
+		"permission.manage-roles":         "Gestionar roles",
+		"permission.manage-messages":      "Gestionar mensajes",
+		"permission.kick-members":         "Expulsar miembros",
+		"permission.ban-members":          "Banear miembros",
+		"permission.embed-links":          "Insertar enlaces",
+		"permission.attach-files":         "Adjuntar archivos",
+		"permission.read-message-history": "Leer el historial de mensajes",
+
+		"locale.argument.locale":       "Un código de idioma como `en` o `es`, o `default`.",
+		"maintenance.argument.target":  "Un nombre de plugin o ID de comando.",
+		"maintenance.argument.message": "Un mensaje que verán los usuarios que intenten usarlo.",
+		"data.argument.confirm":        "`confirm` para continuar.",
+		"data.argument.user":           "Una mención o ID de usuario, solo el dueño del bot puede indicar a otra persona.",
+
+		"configure.command.prefix":                 "Cambia el prefijo de comandos de este servidor.",
+		"configure.command.channel-set":            "Limita todos los comandos a un canal, se puede usar para varios canales.",
+		"configure.command.channel-remove":         "Quita un canal de la restricción de canales del servidor.",
+		"configure.command.channel-list":           "Muestra los canales a los que están limitados los comandos.",
+		"configure.command.role-set":               "Limita todos los comandos a miembros con un rol, se puede usar para varios roles.",
+		"configure.command.role-remove":            "Quita un rol de la restricción de roles del servidor.",
+		"configure.command.role-list":              "Muestra los roles a los que están limitados los comandos.",
+		"configure.command.command-enable":         "Activa un comando en este servidor.",
+		"configure.command.command-disable":        "Desactiva un comando en este servidor.",
+		"configure.command.command-channel-set":    "Limita un comando a un canal, se puede usar para varios canales.",
+		"configure.command.command-channel-remove": "Quita un canal de la restricción de canales de un comando.",
+		"configure.command.command-channel-list":   "Muestra los canales a los que está limitado un comando.",
+		"configure.command.command-role-set":       "Limita un comando a miembros con un rol, se puede usar para varios roles.",
+		"configure.command.command-role-remove":    "Quita un rol de la restricción de roles de un comando.",
+		"configure.command.command-role-list":      "Muestra los roles a los que está limitado un comando.",
+		"configure.command.help":                   "Cambia cómo se entrega la ayuda en este servidor: `public`, mensajes privados (`private`) o mensajes temporales (`ephemeral`) que se borran tras unos segundos.",
+		"configure.command.channel-help":           "Cambia cómo se entrega la ayuda en un canal, por encima del ajuste del servidor.",
+		"configure.command.channel-help-remove":    "Hace que un canal vuelva a usar el ajuste de ayuda del servidor.",
+		"configure.command.help-show":              "Muestra cómo se entrega la ayuda en este servidor y sus canales.",
+		"configure.argument.help-mode":             "`public`, `private` o `ephemeral`.",
+		"configure.argument.seconds":               "Segundos antes de borrar la ayuda temporal, 60 por defecto.",
+		"configure.help-set":                       "La ayuda en este servidor ahora se entrega %s.",
+		"configure.channel-help-set":               "La ayuda en <#%s> ahora se entrega %s.",
+		"configure.channel-help-removed":           "<#%s> ahora usa el ajuste de ayuda del servidor, %s.",
+		"configure.all-channels":                   "Todos los canales",
+		"configure.help-mode.public":               "publicada en el canal",
+		"configure.help-mode.private":              "por mensaje privado",
+		"configure.help-mode.ephemeral#one":        "publicada en el canal y borrada tras %d segundo",
+		"configure.help-mode.ephemeral#other":      "publicada en el canal y borrada tras %d segundos",
+		"configure.command.plugin-enable":          "Activa un plugin en este servidor.",
+		"configure.command.plugin-disable":         "Desactiva un plugin en este servidor, no verá mensajes ni aparecerá en la ayuda.",
+		"configure.command.plugin-only":            "Solo permite los plugins indicados en este servidor, usa `all` para volver a permitirlos todos.",
+		"configure.command.plugin-list":            "Muestra los plugins y si están activados en este servidor.",
+		"configure.argument.plugin":                "Un nombre de plugin, como los muestra `listPlugins`.",
+		"configure.argument.plugins":               "Nombres de plugins separados por espacios, o `all`.",
+		"configure.unknown-plugin":                 "Plugin desconocido: %s",
+		"configure.plugin-protected":               "%s no se puede desactivar.",
+		"configure.plugin-enabled":                 "%s activado en este servidor.",
+		"configure.plugin-disabled":                "%s desactivado en este servidor.",
+		"configure.plugins-only":                   "Ahora solo se permiten %s en este servidor.",
+		"configure.plugins-all":                    "Ahora se permiten todos los plugins en este servidor.",
+		"configure.plugin-state.enabled":           "activado",
+		"configure.plugin-state.disabled":          "desactivado",
+		"configure.plugin-state.protected":         "siempre activado",
+		"configure.command.setting-set":            "Cambia un ajuste de un plugin en este servidor.",
+		"configure.command.setting-reset":          "Restablece el valor predeterminado de un ajuste de un plugin en este servidor.",
+		"configure.command.setting-show":           "Muestra los ajustes de un plugin en este servidor.",
+		"configure.argument.settings-plugin":       "Un plugin con ajustes, como aparece en `listPlugins`.",
+		"configure.argument.key":                   "Un ajuste, como aparece en `show`.",
+		"configure.argument.value":                 "El nuevo valor del ajuste.",
+		"configure.no-settings":                    "%s no tiene ajustes.",
+		"configure.unknown-setting":                "%s no tiene ningún ajuste llamado `%s`.",
+		"configure.setting-set":                    "El ajuste `%[2]s` de %[1]s ahora es %[3]s.",
+		"configure.setting-reset":                  "El ajuste `%[2]s` de %[1]s vuelve a su valor predeterminado, %[3]s.",
+		"configure.setting.default":                "%s (predeterminado)",
+		"configure.setting.unset":                  "sin definir",
+		"configure.setting.on":                     "activado",
+		"configure.setting.off":                    "desactivado",
+		"configure.setting.invalid-number":         "El valor debe ser un número entero.",
+		"configure.setting.invalid-bool":           "El valor debe ser `on` u `off`.",
+		"configure.setting.invalid-choice":         "El valor debe ser uno de: %s",
+		"configure.argument.prefix":                "El nuevo prefijo, de hasta 5 caracteres.",
+		"configure.argument.channel":               "Una mención o nombre de canal.",
+		"configure.argument.role":                  "Una mención o nombre de rol.",
+		"configure.argument.command":               "Un activador o ID de comando.",
+		"configure.unknown-command":                "Comando desconocido: %s",
+		"configure.unknown-channel":                "Canal desconocido: %s",
+		"configure.unknown-role":                   "Rol desconocido: %s",
+		"configure.prefix-set":                     "El prefijo de comandos de este servidor ahora es `%s`.",
+		"configure.channel-added":                  "<#%s> añadido a los canales permitidos para %s.",
+		"configure.channel-removed":                "<#%s> quitado de los canales permitidos para %s.",
+		"configure.role-added":                     "@%s añadido a los roles permitidos para %s.",
+		"configure.role-removed":                   "@%s quitado de los roles permitidos para %s.",
+		"configure.command-enabled":                "%s activado.",
+		"configure.command-disabled":               "%s desactivado.",
+		"configure.unrestricted":                   "sin restricción",
+		"configure.all-commands":                   "todos los comandos",
+	})
+}
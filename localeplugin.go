@@ -0,0 +1,214 @@
+package mutterblack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+const localePluginName = "Locale"
+
+// localeKey is the key of a user's locale in their storage bucket.
+const localeKey = "locale"
+
+type localePlugin struct {
+	sync.RWMutex
+	// Users is the locale of each user, from before they were kept in storage.
+	Users map[string]string `json:",omitempty"`
+
+	bot *Bot
+}
+
+func (p *localePlugin) Name() string {
+	return localePluginName
+}
+
+func (p *localePlugin) Commands() []CommandDefinition {
+	return []CommandDefinition{
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "locale-user-set",
+			Triggers: []string{
+				"language",
+				"locale",
+			},
+			Arguments: []CommandDefinitionArgument{
+				CommandDefinitionArgument{
+					Pattern:     "[a-zA-Z_-]+",
+					Alias:       "locale",
+					Type:        ArgumentTypeWord,
+					Description: "locale.argument.locale",
+				},
+			},
+			Examples: []string{
+				"es",
+				"default",
+			},
+			Description: "locale.command.user-set",
+			Callback:    p.runSetUserLocaleCommand,
+		},
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "locale-user-show",
+			Triggers: []string{
+				"language",
+				"locale",
+			},
+			Description: "locale.command.user-show",
+			Callback:    p.runShowLocaleCommand,
+		},
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "locale-guild-set",
+			Triggers: []string{
+				"serverlanguage",
+				"serverlocale",
+			},
+			Arguments: []CommandDefinitionArgument{
+				CommandDefinitionArgument{
+					Pattern:     "[a-zA-Z_-]+",
+					Alias:       "locale",
+					Type:        ArgumentTypeWord,
+					Description: "locale.argument.locale",
+				},
+			},
+			Examples: []string{
+				"es",
+			},
+			ModeratorOnly: true,
+			Description:   "locale.command.guild-set",
+			Callback:      p.runSetGuildLocaleCommand,
+		},
+	}
+}
+
+func (p *localePlugin) userLocale(userID string) string {
+	if p.bot == nil {
+		return ""
+	}
+
+	locale := ""
+	p.bot.Storage(p).User(userID).GetJSON(localeKey, &locale)
+	return locale
+}
+
+func (p *localePlugin) availableLocales() string {
+	available := []string{}
+	for _, locale := range SupportedLocales() {
+		available = append(available, fmt.Sprintf("`%s` (%s)", locale, LocaleName(locale)))
+	}
+	return strings.Join(available, ", ")
+}
+
+func (p *localePlugin) runShowLocaleCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	l := bot.Localizer(message)
+
+	client.SendMessage(message.Channel(), strings.Join([]string{
+		l.T("locale.current", LocaleName(l.Locale)),
+		l.N("locale.available", len(SupportedLocales()), p.availableLocales()),
+	}, "\n"))
+}
+
+func (p *localePlugin) runSetUserLocaleCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	if isDefaultLocaleArgument(args["locale"]) {
+		bot.Storage(p).User(message.UserID()).Delete(localeKey)
+
+		l := bot.Localizer(message)
+		client.SendMessage(message.Channel(), l.T("locale.user-reset", LocaleName(l.Locale)))
+		return
+	}
+
+	locale, ok := MatchLocale(args["locale"])
+	if !ok {
+		l := bot.Localizer(message)
+		client.SendMessage(message.Channel(), l.T("locale.unknown", args["locale"], p.availableLocales()))
+		return
+	}
+
+	if err := bot.Storage(p).User(message.UserID()).PutJSON(localeKey, locale); err != nil {
+		client.SendMessage(message.Channel(), NewLocalizer(locale).T(err.Error()))
+		return
+	}
+
+	client.SendMessage(message.Channel(), NewLocalizer(locale).T("locale.user-set", LocaleName(locale)))
+}
+
+func (p *localePlugin) runSetGuildLocaleCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	l := bot.Localizer(message)
+
+	locale := ""
+	if !isDefaultLocaleArgument(args["locale"]) {
+		matched, ok := MatchLocale(args["locale"])
+		if !ok {
+			client.SendMessage(message.Channel(), l.T("locale.unknown", args["locale"], p.availableLocales()))
+			return
+		}
+		locale = matched
+	}
+
+	config := bot.Core.editGuildConfiguration(client.ChannelGuildID(message.Channel()))
+	if config == nil {
+		client.SendMessage(message.Channel(), l.T(InterProcessCommunicationFailure))
+		return
+	}
+
+	config.Locale = locale
+	if err := bot.Core.saveGuildConfiguration(config); err != nil {
+		client.SendMessage(message.Channel(), l.T(err.Error()))
+		return
+	}
+
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	client.SendMessage(message.Channel(), NewLocalizer(locale).T("locale.guild-set", LocaleName(locale)))
+}
+
+func isDefaultLocaleArgument(locale string) bool {
+	locale = strings.ToLower(locale)
+	return locale == "default" || locale == "reset"
+}
+
+// Load will load plugin state from a byte array, moving locales saved by older versions into storage.
+func (p *localePlugin) Load(bot *Bot, client *Discord, data []byte) error {
+	p.Lock()
+	defer p.Unlock()
+
+	p.bot = bot
+
+	if data != nil {
+		if err := json.Unmarshal(data, p); err != nil {
+			log.Println("Error loading data", err)
+		}
+	}
+
+	if len(p.Users) == 0 {
+		return nil
+	}
+
+	storage := bot.Storage(p)
+	for userID, locale := range p.Users {
+		if err := storage.User(userID).PutJSON(localeKey, locale); err != nil {
+			return err
+		}
+	}
+	p.Users = nil
+	bot.MarkDirty(p)
+
+	return nil
+}
+
+// Save will save plugin state to a byte array.
+func (p *localePlugin) Save() ([]byte, error) {
+	p.RLock()
+	defer p.RUnlock()
+
+	return json.Marshal(p)
+}
+
+// NewLocalePlugin will create a new locale plugin, which lets users and moderators choose the language the bot replies in.
+func NewLocalePlugin() Plugin {
+	return &localePlugin{}
+}
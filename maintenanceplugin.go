@@ -0,0 +1,308 @@
+package mutterblack
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maintenancePluginName = "Maintenance"
+
+// Maintenance entries are kept in the global storage bucket, keyed by what they disable.
+const (
+	maintenancePluginPrefix  = "plugin/"
+	maintenanceCommandPrefix = "command/"
+)
+
+type maintenanceEntry struct {
+	Message    string
+	DisabledAt time.Time
+}
+
+type maintenancePlugin struct {
+	sync.RWMutex
+	// Storage holds entries saved before they were kept in storage, by storage key, until Load puts them there.
+	Storage map[string]*maintenanceEntry `json:",omitempty"`
+}
+
+func (p *maintenancePlugin) Name() string {
+	return maintenancePluginName
+}
+
+func (p *maintenancePlugin) Commands() []CommandDefinition {
+	return []CommandDefinition{
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "maintenance-disable",
+			Triggers: []string{
+				"globaldisable",
+			},
+			Arguments: []CommandDefinitionArgument{
+				CommandDefinitionArgument{
+					Pattern:     "[^ ]+",
+					Alias:       "target",
+					Type:        ArgumentTypeWord,
+					Description: "maintenance.argument.target",
+				},
+				CommandDefinitionArgument{
+					Optional:    true,
+					Pattern:     ".+",
+					Alias:       "message",
+					Description: "maintenance.argument.message",
+				},
+			},
+			Examples: []string{
+				"PS2Stats",
+				"ps2-outfit The census API is down, check back later.",
+			},
+			OwnerOnly:   true,
+			Description: "maintenance.command.disable",
+			Callback:    p.runDisableCommand,
+		},
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "maintenance-enable",
+			Triggers: []string{
+				"globalenable",
+			},
+			Arguments: []CommandDefinitionArgument{
+				CommandDefinitionArgument{
+					Pattern:     "[^ ]+",
+					Alias:       "target",
+					Type:        ArgumentTypeWord,
+					Description: "maintenance.argument.target",
+				},
+			},
+			Examples: []string{
+				"PS2Stats",
+			},
+			OwnerOnly:   true,
+			Description: "maintenance.command.enable",
+			Callback:    p.runEnableCommand,
+		},
+		CommandDefinition{
+			CommandGroup: p.Name(),
+			CommandID:    "maintenance-list",
+			Triggers: []string{
+				"maintenance",
+			},
+			OwnerOnly:   true,
+			Description: "maintenance.command.list",
+			Callback:    p.runListCommand,
+		},
+	}
+}
+
+// disabled returns the maintenance entry that disables a plugin or one of its commands, plugin wide entries win.
+func (p *maintenancePlugin) disabled(bot *Bot, pluginName string, commandID string) *maintenanceEntry {
+	bucket := bot.Storage(p).Global()
+
+	if entry := maintenanceEntryAt(bucket, maintenancePluginPrefix+pluginName); entry != nil {
+		return entry
+	}
+	if commandID != "" {
+		return maintenanceEntryAt(bucket, maintenanceCommandPrefix+commandID)
+	}
+	return nil
+}
+
+// maintenanceEntryAt returns the maintenance entry stored at key, if any.
+func maintenanceEntryAt(bucket *Bucket, key string) *maintenanceEntry {
+	entry := &maintenanceEntry{}
+	if ok, err := bucket.GetJSON(key, entry); !ok || err != nil {
+		return nil
+	}
+	return entry
+}
+
+// maintenanceKey returns the storage key of the entry for a plugin or command.
+func maintenanceKey(name string, isPlugin bool) string {
+	if isPlugin {
+		return maintenancePluginPrefix + name
+	}
+	return maintenanceCommandPrefix + name
+}
+
+// resolveTarget finds the plugin name or command ID the owner referred to.
+func (p *maintenancePlugin) resolveTarget(bot *Bot, target string) (name string, isPlugin bool, ok bool) {
+	for _, plugin := range bot.Plugins {
+		if strings.EqualFold(plugin.Name(), target) {
+			return plugin.Name(), true, true
+		}
+	}
+
+	for _, plugin := range bot.Plugins {
+		for _, commandDefinition := range PluginCommands(plugin) {
+			if strings.EqualFold(commandDefinition.CommandID, target) {
+				return commandDefinition.CommandID, false, true
+			}
+		}
+	}
+
+	return "", false, false
+}
+
+func (p *maintenancePlugin) runDisableCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	l := bot.Localizer(message)
+
+	name, isPlugin, ok := p.resolveTarget(bot, args["target"])
+	if !ok {
+		client.SendMessage(message.Channel(), l.T("maintenance.unknown-target", args["target"]))
+		return
+	}
+
+	if name == p.Name() {
+		client.SendMessage(message.Channel(), l.T("maintenance.cannot-disable"))
+		return
+	}
+
+	entry := &maintenanceEntry{
+		Message:    strings.TrimSpace(args["message"]),
+		DisabledAt: time.Now().UTC(),
+	}
+
+	if err := bot.Storage(p).Global().PutJSON(maintenanceKey(name, isPlugin), entry); err != nil {
+		client.SendMessage(message.Channel(), l.T(err.Error()))
+		return
+	}
+
+	client.SendMessage(message.Channel(), l.T("maintenance.disabled", name))
+}
+
+func (p *maintenancePlugin) runEnableCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	l := bot.Localizer(message)
+
+	name, isPlugin, ok := p.resolveTarget(bot, args["target"])
+	if !ok {
+		client.SendMessage(message.Channel(), l.T("maintenance.unknown-target", args["target"]))
+		return
+	}
+
+	bot.Storage(p).Global().Delete(maintenanceKey(name, isPlugin))
+
+	client.SendMessage(message.Channel(), l.T("maintenance.enabled", name))
+}
+
+func (p *maintenancePlugin) runListCommand(bot *Bot, client *Discord, message Message, args map[string]string, trigger string) {
+	l := bot.Localizer(message)
+
+	bucket := bot.Storage(p).Global()
+
+	lines := []string{}
+	for _, key := range bucket.List(maintenancePluginPrefix) {
+		if entry := maintenanceEntryAt(bucket, key); entry != nil {
+			lines = append(lines, l.T("maintenance.list-plugin", strings.TrimPrefix(key, maintenancePluginPrefix), entry.DisabledAt.Format(time.RFC822), entry.Message))
+		}
+	}
+	for _, key := range bucket.List(maintenanceCommandPrefix) {
+		if entry := maintenanceEntryAt(bucket, key); entry != nil {
+			lines = append(lines, l.T("maintenance.list-command", strings.TrimPrefix(key, maintenanceCommandPrefix), entry.DisabledAt.Format(time.RFC822), entry.Message))
+		}
+	}
+
+	if len(lines) == 0 {
+		client.SendMessage(message.Channel(), l.T("maintenance.none"))
+		return
+	}
+
+	sort.Strings(lines)
+	client.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+// Migrations converts the data of older versions.
+// Version 0 kept the entries in DisabledPlugins and DisabledCommands, version 1 keeps them in storage.
+func (p *maintenancePlugin) Migrations() []Migration {
+	return []Migration{migrateMaintenanceToStorage}
+}
+
+// migrateMaintenanceToStorage keys the entries of version 0 by their storage key, for Load to put them in storage.
+func migrateMaintenanceToStorage(data json.RawMessage) (json.RawMessage, error) {
+	legacy := struct {
+		DisabledPlugins  map[string]*maintenanceEntry
+		DisabledCommands map[string]*maintenanceEntry
+	}{}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, err
+	}
+
+	migrated := &maintenancePlugin{}
+	for name, entry := range legacy.DisabledPlugins {
+		migrated.store(maintenanceKey(name, true), entry)
+	}
+	for name, entry := range legacy.DisabledCommands {
+		migrated.store(maintenanceKey(name, false), entry)
+	}
+	return json.Marshal(migrated)
+}
+
+func (p *maintenancePlugin) store(key string, entry *maintenanceEntry) {
+	if p.Storage == nil {
+		p.Storage = make(map[string]*maintenanceEntry)
+	}
+	p.Storage[key] = entry
+}
+
+// Load will load plugin state from a byte array, putting entries saved by older versions in storage.
+func (p *maintenancePlugin) Load(bot *Bot, client *Discord, data []byte) error {
+	p.Lock()
+	defer p.Unlock()
+
+	if data != nil {
+		if err := json.Unmarshal(data, p); err != nil {
+			log.Println("Error loading data", err)
+		}
+	}
+
+	if len(p.Storage) == 0 {
+		return nil
+	}
+
+	bucket := bot.Storage(p).Global()
+	for key, entry := range p.Storage {
+		if err := bucket.PutJSON(key, entry); err != nil {
+			return err
+		}
+	}
+	p.Storage = nil
+	bot.MarkDirty(p)
+
+	return nil
+}
+
+// Save will save plugin state to a byte array.
+func (p *maintenancePlugin) Save() ([]byte, error) {
+	p.RLock()
+	defer p.RUnlock()
+
+	return json.Marshal(p)
+}
+
+// NewMaintenancePlugin will create a new maintenance plugin, which lets the bot owner disable commands or whole plugins at runtime.
+func NewMaintenancePlugin() Plugin {
+	return &maintenancePlugin{}
+}
+
+// maintenance returns the maintenance entry that disables a plugin or command, if any.
+func (b *Bot) maintenance(plugin Plugin, commandDefinition *CommandDefinition) *maintenanceEntry {
+	p, ok := b.Plugins[maintenancePluginName].(*maintenancePlugin)
+	if !ok || plugin == p {
+		return nil
+	}
+
+	commandID := ""
+	if commandDefinition != nil {
+		commandID = commandDefinition.CommandID
+	}
+	return p.disabled(b, plugin.Name(), commandID)
+}
+
+// notice returns the text shown to users who invoke something that is disabled.
+func (e *maintenanceEntry) notice(l *Localizer) string {
+	if e.Message != "" {
+		return l.T("maintenance.notice-message", e.Message)
+	}
+	return l.T("maintenance.notice")
+}
@@ -0,0 +1,80 @@
+package mutterblack
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// envelopeFormat marks saved data as wrapped in a dataEnvelope, data saved before envelopes existed is version 0.
+const envelopeFormat = "mutterblack/v1"
+
+// dataEnvelope wraps the data of a Persister with the version it was saved at.
+type dataEnvelope struct {
+	Format  string          `json:"format"`
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Migration converts the data of a plugin from one version to the next.
+type Migration func(data json.RawMessage) (json.RawMessage, error)
+
+// Migrator is implemented by persisters whose saved data has changed shape.
+// Migrations()[i] converts data saved at version i to version i+1, so the current version is the number of migrations.
+// Migrations are only ever added to the end, data saved by older versions is brought up to date by applying them in order.
+type Migrator interface {
+	Migrations() []Migration
+}
+
+// pluginDataVersion returns the version a plugin saves its data at.
+func pluginDataVersion(plugin Plugin) int {
+	if migrator, ok := plugin.(Migrator); ok {
+		return len(migrator.Migrations())
+	}
+	return 0
+}
+
+// wrapData wraps data saved by a plugin in an envelope with the plugin's current version.
+func wrapData(plugin Plugin, data []byte) ([]byte, error) {
+	return json.Marshal(dataEnvelope{
+		Format:  envelopeFormat,
+		Version: pluginDataVersion(plugin),
+		Data:    data,
+	})
+}
+
+// unwrapData returns the data inside an envelope and its version, data without an envelope is returned as version 0.
+func unwrapData(data []byte) (json.RawMessage, int) {
+	envelope := dataEnvelope{}
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Format != envelopeFormat {
+		return data, 0
+	}
+	return envelope.Data, envelope.Version
+}
+
+// migrateData unwraps data saved for a plugin and applies every migration from its version to the current one.
+func migrateData(plugin Plugin, data []byte) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	payload, version := unwrapData(data)
+
+	var migrations []Migration
+	if migrator, ok := plugin.(Migrator); ok {
+		migrations = migrator.Migrations()
+	}
+
+	if version > len(migrations) {
+		return nil, fmt.Errorf("saved at version %d, newer than version %d", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		migrated, err := migrations[i](payload)
+		if err != nil {
+			return nil, fmt.Errorf("migrating from version %d to %d: %v", i, i+1, err)
+		}
+		payload = migrated
+	}
+
+	return payload, nil
+}
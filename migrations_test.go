@@ -0,0 +1,182 @@
+package mutterblack
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type migratingPlugin struct {
+	migrations []Migration
+}
+
+func (p *migratingPlugin) Name() string {
+	return "Migrating"
+}
+
+func (p *migratingPlugin) Migrations() []Migration {
+	return p.migrations
+}
+
+type plainPlugin struct{}
+
+func (p *plainPlugin) Name() string {
+	return "Plain"
+}
+
+// renameMigration moves the value of a field to a new name.
+func renameMigration(from string, to string) Migration {
+	return func(data json.RawMessage) (json.RawMessage, error) {
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		fields[to] = fields[from]
+		delete(fields, from)
+		return json.Marshal(fields)
+	}
+}
+
+// testMigrations are the steps of a plugin at version 2, a field saved as "a" at version 0 is "b" at version 1 and "c" at version 2.
+var testMigrations = []Migration{
+	renameMigration("a", "b"),
+	renameMigration("b", "c"),
+}
+
+func envelope(t *testing.T, version int, data string) []byte {
+	wrapped, err := json.Marshal(dataEnvelope{
+		Format:  envelopeFormat,
+		Version: version,
+		Data:    json.RawMessage(data),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wrapped
+}
+
+func TestMigrationSteps(t *testing.T) {
+	tests := []struct {
+		version int
+		data    string
+		want    string
+	}{
+		{0, `{"a":1}`, `{"b":1}`},
+		{1, `{"b":1}`, `{"c":1}`},
+	}
+
+	for _, test := range tests {
+		got, err := testMigrations[test.version](json.RawMessage(test.data))
+		if err != nil {
+			t.Errorf("v%d to v%d: unexpected error %v", test.version, test.version+1, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("v%d to v%d: got %s, want %s", test.version, test.version+1, got, test.want)
+		}
+	}
+}
+
+func TestMigrateData(t *testing.T) {
+	failing := []Migration{
+		testMigrations[0],
+		func(data json.RawMessage) (json.RawMessage, error) {
+			return nil, errors.New("broken")
+		},
+	}
+
+	tests := []struct {
+		name    string
+		plugin  Plugin
+		data    []byte
+		want    string
+		wantErr string
+	}{
+		{name: "nothing saved", plugin: &migratingPlugin{testMigrations}, data: nil, want: ""},
+		{name: "legacy without migrations", plugin: &plainPlugin{}, data: []byte(`{"a":1}`), want: `{"a":1}`},
+		{name: "legacy is version 0", plugin: &migratingPlugin{testMigrations}, data: []byte(`{"a":1}`), want: `{"c":1}`},
+		{name: "envelope at version 0", plugin: &migratingPlugin{testMigrations}, data: envelope(t, 0, `{"a":1}`), want: `{"c":1}`},
+		{name: "envelope at version 1", plugin: &migratingPlugin{testMigrations}, data: envelope(t, 1, `{"b":1}`), want: `{"c":1}`},
+		{name: "envelope at current version", plugin: &migratingPlugin{testMigrations}, data: envelope(t, 2, `{"c":1}`), want: `{"c":1}`},
+		{name: "envelope newer than the plugin", plugin: &migratingPlugin{testMigrations}, data: envelope(t, 3, `{"d":1}`), wantErr: "newer than version 2"},
+		{name: "envelope newer than a plugin without migrations", plugin: &plainPlugin{}, data: envelope(t, 1, `{"b":1}`), wantErr: "newer than version 0"},
+		{name: "failing migration", plugin: &migratingPlugin{failing}, data: envelope(t, 0, `{"a":1}`), wantErr: "from version 1 to 2: broken"},
+	}
+
+	for _, test := range tests {
+		got, err := migrateData(test.plugin, test.data)
+
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: got error %v, want one containing %q", test.name, err, test.wantErr)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestWrapDataRoundTrip(t *testing.T) {
+	plugin := &migratingPlugin{testMigrations}
+
+	wrapped, err := wrapData(plugin, []byte(`{"c":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := dataEnvelope{}
+	if err := json.Unmarshal(wrapped, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Format != envelopeFormat || saved.Version != 2 {
+		t.Errorf("got format %q version %d, want %q version 2", saved.Format, saved.Version, envelopeFormat)
+	}
+
+	got, err := migrateData(plugin, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"c":1}` {
+		t.Errorf("got %s, want {\"c\":1}", got)
+	}
+}
+
+func TestPluginMigrations(t *testing.T) {
+	tests := []struct {
+		name   string
+		plugin Plugin
+		data   []byte
+		want   string
+	}{
+		{name: "help without settings", plugin: NewHelpPlugin(), data: []byte(`{}`), want: `{}`},
+		{name: "help settings", plugin: NewHelpPlugin(), data: []byte(`{"Private":{"1":true,"2":false}}`), want: `{"Storage":{"private/1":true,"private/2":false}}`},
+		{name: "help settings in an envelope", plugin: NewHelpPlugin(), data: envelope(t, 0, `{"Private":{"1":true}}`), want: `{"Storage":{"private/1":true}}`},
+		{name: "help at version 1", plugin: NewHelpPlugin(), data: envelope(t, 1, `{"Storage":{"private/1":true}}`), want: `{"Storage":{"private/1":true}}`},
+		{name: "maintenance without entries", plugin: NewMaintenancePlugin(), data: []byte(`{"DisabledPlugins":{},"DisabledCommands":{}}`), want: `{}`},
+		{
+			name:   "maintenance entries",
+			plugin: NewMaintenancePlugin(),
+			data:   []byte(`{"DisabledPlugins":{"Weather":{"Message":"m","DisabledAt":"2018-01-02T03:04:05Z"}},"DisabledCommands":{"ps2-character":{"Message":"","DisabledAt":"2018-01-02T03:04:05Z"}}}`),
+			want:   `{"Storage":{"command/ps2-character":{"Message":"","DisabledAt":"2018-01-02T03:04:05Z"},"plugin/Weather":{"Message":"m","DisabledAt":"2018-01-02T03:04:05Z"}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := migrateData(test.plugin, test.data)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package mutterblack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// MaxMessageLength is the longest text message Discord accepts.
+	MaxMessageLength = 2000
+
+	paginatorPrevious = "◀"
+	paginatorNext     = "▶"
+	paginatorTimeout  = 5 * time.Minute
+)
+
+type paginator struct {
+	sync.Mutex
+	channelID string
+	messageID string
+	userID    string
+	pages     []*discordgo.MessageEmbed
+	page      int
+}
+
+type paginators struct {
+	sync.RWMutex
+	byMessageID map[string]*paginator
+}
+
+// SendPaginatedEmbed sends the first page of embeds and lets userID flip through the rest by reacting to it.
+// Navigation stops working after a few minutes. It returns the ID of the message that was sent.
+func (d *Discord) SendPaginatedEmbed(channel string, userID string, pages []*discordgo.MessageEmbed) (string, error) {
+	if len(pages) == 0 {
+		return "", nil
+	}
+
+	if channel == "" {
+		log.Println("Empty channel could not send message", pages[0])
+		return "", nil
+	}
+
+	m, err := d.Session.ChannelMessageSendEmbed(channel, pages[0])
+	if err != nil {
+		log.Println("Error sending discord embed message: ", err)
+		return "", err
+	}
+
+	if len(pages) == 1 {
+		return m.ID, nil
+	}
+
+	p := &paginator{
+		channelID: channel,
+		messageID: m.ID,
+		userID:    userID,
+		pages:     pages,
+	}
+
+	d.paginators.Lock()
+	d.paginators.byMessageID[m.ID] = p
+	d.paginators.Unlock()
+
+	d.Session.MessageReactionAdd(channel, m.ID, paginatorPrevious)
+	d.Session.MessageReactionAdd(channel, m.ID, paginatorNext)
+
+	time.AfterFunc(paginatorTimeout, func() {
+		d.paginators.Lock()
+		delete(d.paginators.byMessageID, m.ID)
+		d.paginators.Unlock()
+
+		d.Session.MessageReactionsRemoveAll(channel, m.ID)
+	})
+
+	return m.ID, nil
+}
+
+func (d *Discord) onPaginatorReaction(s *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
+	d.paginators.RLock()
+	p, ok := d.paginators.byMessageID[reaction.MessageID]
+	d.paginators.RUnlock()
+
+	if !ok || reaction.UserID != p.userID {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	page := p.page
+	switch reaction.Emoji.Name {
+	case paginatorPrevious:
+		page--
+	case paginatorNext:
+		page++
+	default:
+		return
+	}
+
+	s.MessageReactionRemove(p.channelID, p.messageID, reaction.Emoji.Name, reaction.UserID)
+
+	if page < 0 || page >= len(p.pages) {
+		return
+	}
+
+	if _, err := s.ChannelMessageEditEmbed(p.channelID, p.messageID, p.pages[page]); err != nil {
+		log.Println("Error editing discord embed message: ", err)
+		return
+	}
+	p.page = page
+}
+
+// CanSendEmbed returns true if the bot is allowed to post embeds in a channel.
+func (d *Discord) CanSendEmbed(channel string) bool {
+	c, err := d.Channel(channel)
+	if err == nil && c != nil && c.Type == discordgo.ChannelTypeDM {
+		return true
+	}
+
+	p, err := d.UserChannelPermissions(d.UserID(), channel)
+	if err != nil {
+		return false
+	}
+	return p&discordgo.PermissionEmbedLinks == discordgo.PermissionEmbedLinks
+}
+
+// SendMessageChunks sends lines in as few messages as possible without exceeding MaxMessageLength.
+// It returns the IDs of the messages that were sent.
+func (d *Discord) SendMessageChunks(channel string, lines []string) ([]string, error) {
+	messageIDs := []string{}
+
+	if channel == "" {
+		log.Println("Empty channel could not send message", lines)
+		return messageIDs, nil
+	}
+
+	for _, chunk := range chunkLines(lines, MaxMessageLength) {
+		m, err := d.Session.ChannelMessageSend(channel, chunk)
+		if err != nil {
+			log.Println("Error sending discord message: ", err)
+			return messageIDs, err
+		}
+		messageIDs = append(messageIDs, m.ID)
+	}
+	return messageIDs, nil
+}
+
+func chunkLines(lines []string, limit int) []string {
+	chunks := []string{}
+	current := ""
+
+	for _, line := range lines {
+		for len(line) > limit {
+			if current != "" {
+				chunks = append(chunks, current)
+				current = ""
+			}
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			chunks = append(chunks, line[:cut])
+			line = line[cut:]
+		}
+
+		if current == "" {
+			current = line
+		} else if len(current)+1+len(line) <= limit {
+			current = current + "\n" + line
+		} else {
+			chunks = append(chunks, current)
+			current = line
+		}
+	}
+
+	if current != "" {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
+
+// EmbedText renders an embed as plain text lines, for channels the bot cannot post embeds in.
+func EmbedText(embed *discordgo.MessageEmbed) []string {
+	lines := []string{}
+
+	if embed.Title != "" {
+		lines = append(lines, fmt.Sprintf("**%s**", embed.Title))
+	}
+	if embed.Description != "" {
+		lines = append(lines, embed.Description)
+	}
+	for _, field := range embed.Fields {
+		lines = append(lines, fmt.Sprintf("__%s__", field.Name))
+		lines = append(lines, strings.Split(field.Value, "\n")...)
+	}
+	if embed.Footer != nil && embed.Footer.Text != "" {
+		lines = append(lines, fmt.Sprintf("*%s*", embed.Footer.Text))
+	}
+
+	return lines
+}
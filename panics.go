@@ -0,0 +1,210 @@
+package mutterblack
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// panicNoticeInterval is the least time between two panic notices to the owner.
+	panicNoticeInterval = 5 * time.Minute
+	panicInputLength    = 200
+	panicStackLength    = 1200
+)
+
+// panicReport aggregates the panics that happened at one location.
+type panicReport struct {
+	Plugin   string
+	Command  string
+	Location string
+	Value    string
+	Input    string
+	Stack    string
+	Count    int
+	Last     time.Time
+
+	reported int
+}
+
+type panicReporter struct {
+	sync.Mutex
+	reports    map[string]*panicReport
+	lastNotice time.Time
+	scheduled  bool
+}
+
+// protect calls fn and recovers a panic in it, reporting it as coming from entrypoint of plugin.
+// Input is what the user sent, if anything, and is sanitized before it is reported. It returns true if fn panicked.
+func (b *Bot) protect(plugin Plugin, entrypoint string, input string, fn func()) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			b.reportPanic(plugin, entrypoint, input, r, panicLocation(), string(debug.Stack()))
+		}
+	}()
+
+	fn()
+	return false
+}
+
+// Go runs fn in its own goroutine, a panic in it is recovered and reported as coming from entrypoint of plugin like one in a command.
+// Plugins start their background work, such as polling, with it.
+func (b *Bot) Go(plugin Plugin, entrypoint string, fn func()) {
+	go b.protect(plugin, entrypoint, "", fn)
+}
+
+func (b *Bot) reportPanic(plugin Plugin, entrypoint string, input string, value interface{}, location string, stack string) {
+	pluginName := ""
+	if plugin != nil {
+		pluginName = plugin.Name()
+	}
+
+	log.Printf("Recovered panic in %s %s at %s: %v\n%s", pluginName, entrypoint, location, value, stack)
+
+	p := b.panics
+	p.Lock()
+	defer p.Unlock()
+
+	key := pluginName + "|" + location
+	report, ok := p.reports[key]
+	if !ok {
+		report = &panicReport{
+			Plugin:   pluginName,
+			Location: location,
+		}
+		p.reports[key] = report
+	}
+
+	report.Command = entrypoint
+	report.Value = fmt.Sprint(value)
+	report.Input = sanitizePanicInput(input)
+	report.Stack = stack
+	report.Count++
+	report.Last = time.Now()
+
+	if p.scheduled {
+		return
+	}
+
+	wait := panicNoticeInterval - time.Since(p.lastNotice)
+	if wait < 0 {
+		wait = 0
+	}
+	p.scheduled = true
+	time.AfterFunc(wait, b.sendPanicNotice)
+}
+
+// sendPanicNotice sends the owner every panic report that has new occurrences since the last notice.
+func (b *Bot) sendPanicNotice() {
+	p := b.panics
+	p.Lock()
+	p.scheduled = false
+	p.lastNotice = time.Now()
+
+	reports := []panicReport{}
+	for _, report := range p.reports {
+		if report.Count > report.reported {
+			copied := *report
+			copied.reported = report.Count - report.reported
+			reports = append(reports, copied)
+			report.reported = report.Count
+		}
+	}
+	p.Unlock()
+
+	if len(reports) == 0 {
+		return
+	}
+
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Last.Before(reports[j].Last) })
+
+	l := NewLocalizer(DefaultLocale)
+
+	lines := []string{}
+	for _, report := range reports {
+		stack := report.Stack
+		if len(stack) > panicStackLength {
+			stack = stack[:panicStackLength] + "…"
+		}
+
+		lines = append(lines, l.N("panic.report", report.reported, report.Plugin, report.Command, report.reported, report.Count, report.Location))
+		lines = append(lines, l.T("panic.value", report.Value))
+		if report.Input != "" {
+			lines = append(lines, l.T("panic.input", report.Input))
+		}
+		lines = append(lines, "```\n"+strings.Replace(stack, "```", "'''", -1)+"\n```")
+	}
+
+	b.notifyOwner(lines)
+}
+
+// notifyOwner sends lines to PanicChannelID, or to the owner privately when it is empty.
+func (b *Bot) notifyOwner(lines []string) {
+	channel := b.PanicChannelID
+	if channel == "" {
+		if b.Client.OwnerUserID == "" {
+			return
+		}
+
+		privateChannel, err := b.Client.PrivateChannelID(b.Client.OwnerUserID)
+		if err != nil {
+			log.Println("Error creating private channel", err)
+			return
+		}
+		channel = privateChannel
+	}
+
+	b.Client.SendMessageChunks(channel, lines)
+}
+
+// panicLocation returns the file and line that panicked, it must be called from the deferred function that recovered.
+func panicLocation() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	afterPanic := false
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.gopanic" {
+			afterPanic = true
+		} else if afterPanic && !strings.HasPrefix(frame.Function, "runtime.") {
+			return fmt.Sprintf("%s:%d", trimSourcePath(frame.File), frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// trimSourcePath shortens a source file path to its file and directory.
+func trimSourcePath(file string) string {
+	return filepath.Join(filepath.Base(filepath.Dir(file)), filepath.Base(file))
+}
+
+var panicMentionPattern = regexp.MustCompile(`<@[!&]?\d+>`)
+
+// sanitizePanicInput makes user input safe to show in a panic notice, it can't mention anyone or break out of formatting.
+func sanitizePanicInput(input string) string {
+	input = panicMentionPattern.ReplaceAllString(input, "@mention")
+	input = strings.Replace(input, "@everyone", "@\u200beveryone", -1)
+	input = strings.Replace(input, "@here", "@\u200bhere", -1)
+	input = strings.Replace(input, "`", "'", -1)
+	input = strings.Join(strings.Fields(input), " ")
+
+	if len(input) > panicInputLength {
+		cut := panicInputLength
+		for cut > 0 && input[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		input = input[:cut] + "…"
+	}
+	return input
+}
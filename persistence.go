@@ -0,0 +1,182 @@
+package mutterblack
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// defaultSaveDelay is how long changes are collected before they are saved.
+const defaultSaveDelay = 10 * time.Second
+
+// saveState tracks the plugins that changed since they were last saved and how the last save went.
+type saveState struct {
+	sync.Mutex
+	dirty     map[string]bool
+	scheduled bool
+	closed    bool
+	last      time.Time
+	lastErr   error
+
+	// writing is held while data is written, so a scheduled save and Save never write at the same time.
+	writing sync.Mutex
+}
+
+// SaveStatus describes the last save of plugin data.
+type SaveStatus struct {
+	// Last is when data was last saved, it is zero until the first save.
+	Last time.Time
+	// Err is the first error of the last save, if any.
+	Err error
+	// Pending is the number of plugins whose data or storage changed and is not saved yet.
+	Pending int
+}
+
+// MarkDirty records that the data of a Persister changed, it is saved within SaveDelay together with any other changes.
+// Changes to a plugin's Storage are tracked by the bot and don't need to be marked.
+func (b *Bot) MarkDirty(plugin Plugin) {
+	b.saves.Lock()
+	b.saves.dirty[plugin.Name()] = true
+	b.saves.Unlock()
+
+	b.scheduleSave()
+}
+
+// scheduleSave saves changed data once SaveDelay has passed, unless a save is already scheduled.
+func (b *Bot) scheduleSave() {
+	s := b.saves
+	s.Lock()
+	defer s.Unlock()
+
+	if s.scheduled || s.closed {
+		return
+	}
+	s.scheduled = true
+	time.AfterFunc(b.SaveDelay, b.saveChanged)
+}
+
+// saveChanged saves the plugins marked dirty and the storage that changed.
+func (b *Bot) saveChanged() {
+	s := b.saves
+	s.Lock()
+	s.scheduled = false
+	if s.closed {
+		s.Unlock()
+		return
+	}
+	dirty := s.dirty
+	s.dirty = make(map[string]bool)
+	s.Unlock()
+
+	b.save(func(plugin Plugin) bool {
+		return dirty[plugin.Name()]
+	})
+}
+
+// Save saves every plugin and the storage that changed, whether or not they were marked dirty.
+func (b *Bot) Save() {
+	b.saves.Lock()
+	b.saves.dirty = make(map[string]bool)
+	b.saves.Unlock()
+
+	b.save(func(plugin Plugin) bool {
+		return true
+	})
+}
+
+// save writes the plugins selected by include and all changed storage.
+// Plugins that fail to save are marked dirty again, so they are retried with the next save.
+func (b *Bot) save(include func(plugin Plugin) bool) {
+	s := b.saves
+	s.writing.Lock()
+	defer s.writing.Unlock()
+
+	var saveErr error
+	failed := []Plugin{}
+
+	for _, plugin := range b.orderedPlugins(false) {
+		persister, ok := plugin.(Persister)
+		if !ok || !include(plugin) {
+			continue
+		}
+
+		var err error
+		if b.protect(plugin, "Save", "", func() {
+			err = b.savePlugin(plugin, persister)
+		}) {
+			err = fmt.Errorf("panicked while saving")
+		}
+
+		if err != nil {
+			log.Printf("Error saving plugin %s. %v", plugin.Name(), err)
+			failed = append(failed, plugin)
+			if saveErr == nil {
+				saveErr = fmt.Errorf("%s: %v", plugin.Name(), err)
+			}
+		}
+	}
+
+	storageErr := b.saveStorage()
+	if saveErr == nil {
+		saveErr = storageErr
+	}
+
+	s.Lock()
+	s.last = time.Now()
+	s.lastErr = saveErr
+	for _, plugin := range failed {
+		s.dirty[plugin.Name()] = true
+	}
+	s.Unlock()
+
+	if saveErr != nil {
+		b.scheduleSave()
+	}
+}
+
+// savePlugin writes the data of one plugin.
+func (b *Bot) savePlugin(plugin Plugin, persister Persister) error {
+	data, err := persister.Save()
+	if err != nil || data == nil {
+		return err
+	}
+
+	if data, err = wrapData(plugin, data); err != nil {
+		return err
+	}
+	return b.writeData(plugin.Name(), data)
+}
+
+// closeSaves stops scheduled saves, what changed is saved by the final Save.
+func (b *Bot) closeSaves() {
+	b.saves.Lock()
+	b.saves.closed = true
+	b.saves.Unlock()
+}
+
+// SaveStatus returns how the last save went and how many plugins are waiting to be saved.
+func (b *Bot) SaveStatus() SaveStatus {
+	pending := map[string]bool{}
+	for _, store := range b.pluginStores() {
+		store.RLock()
+		if store.dirty {
+			pending[store.name] = true
+		}
+		store.RUnlock()
+	}
+
+	s := b.saves
+	s.Lock()
+	defer s.Unlock()
+
+	for name := range s.dirty {
+		pending[name] = true
+	}
+
+	return SaveStatus{
+		Last:    s.last,
+		Err:     s.lastErr,
+		Pending: len(pending),
+	}
+}
@@ -1,7 +1,6 @@
 package inviteplugin
 
 import (
-	"fmt"
 	"log"
 	"strings"
 
@@ -16,15 +15,19 @@ func discordInviteID(id string) string {
 }
 
 func InviteHelp(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) (string, string) {
+	l := bot.Localizer(message)
+
 	if client.ApplicationClientID != "" {
-		return "", fmt.Sprintf("Returns a URL to add %s to your server.", client.UserName())
+		return "", l.T("invite.help.url", client.UserName())
 	}
-	return "<discordinvite>", "Joins the provided Discord server."
+	return "<discordinvite>", l.T("invite.help.join")
 }
 
 func InviteCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, command string, parts []string) {
+	l := bot.Localizer(message)
+
 	if client.ApplicationClientID != "" {
-		client.SendMessage(message.Channel(), fmt.Sprintf("Please visit <https://discordapp.com/oauth2/authorize?client_id=%s&scope=bot> to add %s to your server.", client.ApplicationClientID, client.UserName()))
+		client.SendMessage(message.Channel(), l.T("invite.url", client.ApplicationClientID, client.UserName()))
 		return
 	}
 
@@ -33,12 +36,12 @@ func InviteCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mu
 		join = discordInviteID(join)
 		if err := client.Join(join); err != nil {
 			if err == mutterblack.ErrAlreadyJoined {
-				client.PrivateMessage(message.UserID(), "I have already joined that server.")
+				client.PrivateMessage(message.UserID(), l.T("invite.already-joined"))
 				return
 			}
-			log.Println("Error joining discord %v", err)
+			log.Printf("Error joining discord %v", err)
 		} else {
-			client.PrivateMessage(message.UserID(), "I have joined that server.")
+			client.PrivateMessage(message.UserID(), l.T("invite.joined"))
 		}
 	}
 }
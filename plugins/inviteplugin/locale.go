@@ -0,0 +1,23 @@
+package inviteplugin
+
+import (
+	"github.com/lampjaw/mutterblack.discord"
+)
+
+func init() {
+	mutterblack.RegisterCatalog("en", mutterblack.Catalog{
+		"invite.help.url":       "Returns a URL to add %s to your server.",
+		"invite.help.join":      "Joins the provided Discord server.",
+		"invite.url":            "Please visit <https://discordapp.com/oauth2/authorize?client_id=%s&scope=bot> to add %s to your server.",
+		"invite.already-joined": "I have already joined that server.",
+		"invite.joined":         "I have joined that server.",
+	})
+
+	mutterblack.RegisterCatalog("es", mutterblack.Catalog{
+		"invite.help.url":       "Muestra un enlace para añadir a %s a tu servidor.",
+		"invite.help.join":      "Se une al servidor de Discord indicado.",
+		"invite.url":            "Visita <https://discordapp.com/oauth2/authorize?client_id=%s&scope=bot> para añadir a %s a tu servidor.",
+		"invite.already-joined": "Ya estoy en ese servidor.",
+		"invite.joined":         "Me he unido a ese servidor.",
+	})
+}
@@ -0,0 +1,81 @@
+package planetsidetwoplugin
+
+import (
+	"github.com/lampjaw/mutterblack.discord"
+)
+
+func init() {
+	mutterblack.RegisterCatalog("en", mutterblack.Catalog{
+		"ps2.command.character":             "Get stats for a player.",
+		"ps2.command.character-weapon":      "Get weapon stats for a player.",
+		"ps2.command.outfit":                "Get outfit stats by outfit tag.",
+		"ps2.setting.platform":              "Platform used by commands without a platform suffix: `pc`, `ps4us` or `ps4eu`.",
+		"ps2.unavailable":                   "PS2 stats are temporarily unavailable, please try again in a few minutes.",
+		"ps2.full-stats":                    "Click here for full stats",
+		"ps2.field.last-seen":               "Last Seen",
+		"ps2.field.server":                  "Server",
+		"ps2.field.battle-rank":             "Battle Rank",
+		"ps2.field.outfit":                  "Outfit",
+		"ps2.field.kills":                   "Kills",
+		"ps2.field.deaths":                  "Deaths",
+		"ps2.field.play-time":               "Play Time",
+		"ps2.field.score":                   "Score",
+		"ps2.field.kdr":                     "KDR",
+		"ps2.field.hsr":                     "HSR",
+		"ps2.field.kph":                     "KpH",
+		"ps2.field.accuracy":                "Accuracy",
+		"ps2.field.siege-level":             "Siege Level",
+		"ps2.field.ivi-score":               "IVI Score",
+		"ps2.field.ivi-kdr":                 "IVI KDR",
+		"ps2.field.leader":                  "Leader",
+		"ps2.field.member-count":            "Member Count",
+		"ps2.field.activity-7":              "Activity 7 Days",
+		"ps2.field.activity-30":             "Activity 30 Days",
+		"ps2.field.activity-90":             "Activity 90 Days",
+		"ps2.value.last-seen":               "%d-%02d-%02d %02d:%02d:%02d UTC",
+		"ps2.value.play-time-hours":         "%0.1f (%0.1f) Hours",
+		"ps2.value.play-time-minutes#one":   "%d Minute",
+		"ps2.value.play-time-minutes#other": "%d Minutes",
+
+		"ps2.argument.character-name": "The name of the character.",
+		"ps2.argument.weapon-name":    "The full or partial name of the weapon.",
+		"ps2.argument.outfit-alias":   "The outfit tag, up to four characters.",
+	})
+
+	mutterblack.RegisterCatalog("es", mutterblack.Catalog{
+		"ps2.command.character":             "Muestra las estadísticas de un jugador.",
+		"ps2.command.character-weapon":      "Muestra las estadísticas de arma de un jugador.",
+		"ps2.command.outfit":                "Muestra las estadísticas de un outfit por su etiqueta.",
+		"ps2.setting.platform":              "Plataforma de los comandos sin sufijo de plataforma: `pc`, `ps4us` o `ps4eu`.",
+		"ps2.unavailable":                   "Las estadísticas de PS2 no están disponibles temporalmente, inténtalo de nuevo en unos minutos.",
+		"ps2.full-stats":                    "Haz clic aquí para ver todas las estadísticas",
+		"ps2.field.last-seen":               "Última conexión",
+		"ps2.field.server":                  "Servidor",
+		"ps2.field.battle-rank":             "Rango de batalla",
+		"ps2.field.outfit":                  "Outfit",
+		"ps2.field.kills":                   "Bajas",
+		"ps2.field.deaths":                  "Muertes",
+		"ps2.field.play-time":               "Tiempo de juego",
+		"ps2.field.score":                   "Puntuación",
+		"ps2.field.kdr":                     "KDR",
+		"ps2.field.hsr":                     "HSR",
+		"ps2.field.kph":                     "KpH",
+		"ps2.field.accuracy":                "Precisión",
+		"ps2.field.siege-level":             "Nivel de asedio",
+		"ps2.field.ivi-score":               "Puntuación IVI",
+		"ps2.field.ivi-kdr":                 "KDR IVI",
+		"ps2.field.leader":                  "Líder",
+		"ps2.field.member-count":            "Miembros",
+		"ps2.field.activity-7":              "Actividad 7 días",
+		"ps2.field.activity-30":             "Actividad 30 días",
+		"ps2.field.activity-90":             "Actividad 90 días",
+		"ps2.value.last-seen":               "%d-%02d-%02d %02d:%02d:%02d UTC",
+		"ps2.value.play-time-hours":         "%0.1f (%0.1f) horas",
+		"ps2.value.play-time-minutes#one":   "%d minuto",
+		"ps2.value.play-time-minutes#other": "%d minutos",
+
+		"ps2.argument.character-name": "El nombre del personaje.",
+		"ps2.argument.weapon-name":    "El nombre completo o parcial del arma.",
+		"ps2.argument.outfit-alias":   "La etiqueta del outfit, de hasta cuatro caracteres.",
+	})
+}
@@ -3,7 +3,7 @@ package planetsidetwoplugin
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,6 +15,7 @@ const CENSUS_IMAGEBASE_URI = "http://census.daybreakgames.com/files/ps2/images/s
 const VOIDWELL_URI = "https://voidwell.com/"
 
 type planetsidetwoPlugin struct {
+	mutterblack.BasePlugin
 	sync.RWMutex
 }
 
@@ -91,11 +92,16 @@ func (p *planetsidetwoPlugin) Commands() []mutterblack.CommandDefinition {
 			},
 			Arguments: []mutterblack.CommandDefinitionArgument{
 				mutterblack.CommandDefinitionArgument{
-					Pattern: "[a-zA-Z0-9]*",
-					Alias:   "characterName",
+					Pattern:     "[a-zA-Z0-9]*",
+					Alias:       "characterName",
+					Type:        mutterblack.ArgumentTypeWord,
+					Description: "ps2.argument.character-name",
 				},
 			},
-			Description: "Get stats for a player.",
+			Examples: []string{
+				"Lampjaw",
+			},
+			Description: "ps2.command.character",
 			Callback:    p.runCharacterStatsCommand,
 		},
 		mutterblack.CommandDefinition{
@@ -108,15 +114,22 @@ func (p *planetsidetwoPlugin) Commands() []mutterblack.CommandDefinition {
 			},
 			Arguments: []mutterblack.CommandDefinitionArgument{
 				mutterblack.CommandDefinitionArgument{
-					Pattern: "[a-zA-Z0-9]*",
-					Alias:   "characterName",
+					Pattern:     "[a-zA-Z0-9]*",
+					Alias:       "characterName",
+					Type:        mutterblack.ArgumentTypeWord,
+					Description: "ps2.argument.character-name",
 				},
 				mutterblack.CommandDefinitionArgument{
-					Pattern: ".*",
-					Alias:   "weaponName",
+					Pattern:     ".*",
+					Alias:       "weaponName",
+					Type:        mutterblack.ArgumentTypeText,
+					Description: "ps2.argument.weapon-name",
 				},
 			},
-			Description: "Get weapon stats for a player.",
+			Examples: []string{
+				"Lampjaw Gauss SAW",
+			},
+			Description: "ps2.command.character-weapon",
 			Callback:    p.runCharacterWeaponStatsCommand,
 		},
 		mutterblack.CommandDefinition{
@@ -129,136 +142,123 @@ func (p *planetsidetwoPlugin) Commands() []mutterblack.CommandDefinition {
 			},
 			Arguments: []mutterblack.CommandDefinitionArgument{
 				mutterblack.CommandDefinitionArgument{
-					Pattern: "[a-zA-Z0-9]{1,4}",
-					Alias:   "outfitAlias",
+					Pattern:     "[a-zA-Z0-9]{1,4}",
+					Alias:       "outfitAlias",
+					Type:        mutterblack.ArgumentTypeWord,
+					Description: "ps2.argument.outfit-alias",
 				},
 			},
-			Description: "Get outfit stats by outfit tag.",
+			Examples: []string{
+				"WRIT",
+			},
+			Description: "ps2.command.outfit",
 			Callback:    p.runOutfitStatsCommand,
 		},
 	}
 }
 
-func (p *planetsidetwoPlugin) Name() string {
-	return "PS2Stats"
-}
+var platforms = []string{"pc", "ps4us", "ps4eu"}
 
-func (p *planetsidetwoPlugin) Load(bot *mutterblack.Bot, client *mutterblack.Discord, data []byte) error {
-	if data != nil {
-		if err := json.Unmarshal(data, p); err != nil {
-			log.Println("Error loading data", err)
-		}
+func (p *planetsidetwoPlugin) Settings() []mutterblack.Setting {
+	return []mutterblack.Setting{
+		mutterblack.Setting{
+			Key:         "platform",
+			Type:        mutterblack.SettingTypeChoice,
+			Default:     "pc",
+			Description: "ps2.setting.platform",
+			Choices:     platforms,
+		},
 	}
-
-	return nil
 }
 
-func (p *planetsidetwoPlugin) Save() ([]byte, error) {
-	return json.Marshal(p)
-}
-
-func (p *planetsidetwoPlugin) Help(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, detailed bool) []string {
-	return []string{
-		mutterblack.CommandHelp(client, "ps2c", "<character name>", "Get stats for a player.")[0],
-		mutterblack.CommandHelp(client, "ps2c-ps4us", "<character name>", "Get stats for a player.")[0],
-		mutterblack.CommandHelp(client, "ps2c-ps4eu", "<character name>", "Get stats for a player.")[0],
-		mutterblack.CommandHelp(client, "ps2c", "<character name> <weapon name>", "Get weapon stats for a player.")[0],
-		mutterblack.CommandHelp(client, "ps2c-ps4us", "<character name> <weapon name>", "Get weapon stats for a player.")[0],
-		mutterblack.CommandHelp(client, "ps2c-ps4eu", "<character name> <weapon name>", "Get weapon stats for a player.")[0],
-		mutterblack.CommandHelp(client, "ps2o", "<outfit name>", "Get outfit stats")[0],
-		mutterblack.CommandHelp(client, "ps2o-ps4us", "<outfit name>", "Get outfit stats")[0],
-		mutterblack.CommandHelp(client, "ps2o-ps4eu", "<outfit name>", "Get outfit stats")[0],
+// platform returns the platform named by a trigger's suffix, eg. ps2c-ps4eu, or the server's default platform.
+func (p *planetsidetwoPlugin) platform(bot *mutterblack.Bot, message mutterblack.Message, trigger string) string {
+	for _, platform := range platforms {
+		if strings.HasSuffix(trigger, "-"+platform) {
+			return platform
+		}
 	}
-}
-
-func (p *planetsidetwoPlugin) Message(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) {
-
-}
-
-func (p *planetsidetwoPlugin) Stats(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) []string {
-	return nil
+	return bot.Settings(p, message).String("platform")
 }
 
 func New() mutterblack.Plugin {
-	return &planetsidetwoPlugin{}
+	return &planetsidetwoPlugin{
+		BasePlugin: mutterblack.NewBasePlugin("PS2Stats"),
+	}
 }
 
 func (p *planetsidetwoPlugin) runCharacterStatsCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, args map[string]string, trigger string) {
-	if trigger == "ps2c-ps4us" {
-		args["platform"] = "ps4us"
-	} else if trigger == "ps2c-ps4eu" {
-		args["platform"] = "ps4eu"
-	} else {
-		args["platform"] = "pc"
-	}
+	args["platform"] = p.platform(bot, message, trigger)
+
+	l := bot.Localizer(message)
 
-	resp, err := mutterblack.SendCoreCommand("planetside2", "character", args)
+	result, err := bot.Core.CommandResult("planetside2", "character", args)
 
 	if err != nil {
 		p.RLock()
-		client.SendMessage(message.Channel(), fmt.Sprintf("%s", err))
+		client.SendMessage(message.Channel(), mutterblack.CoreErrorMessage(l, err, "ps2.unavailable"))
 		p.RUnlock()
 		return
 	}
 
 	var character PlanetsideCharacter
-	json.Unmarshal(resp, &character)
+	json.Unmarshal(result.Data, &character)
 
 	lastSaved, _ := time.Parse(time.RFC3339, character.LastSaved)
 
 	fields := []*discordgo.MessageEmbedField{
 		&discordgo.MessageEmbedField{
-			Name:   "Last Seen",
-			Value:  fmt.Sprintf("%d-%02d-%02d %02d:%02d:%02d UTC", lastSaved.Year(), lastSaved.Month(), lastSaved.Day(), lastSaved.Hour(), lastSaved.Minute(), lastSaved.Second()),
+			Name:   l.T("ps2.field.last-seen"),
+			Value:  l.T("ps2.value.last-seen", lastSaved.Year(), lastSaved.Month(), lastSaved.Day(), lastSaved.Hour(), lastSaved.Minute(), lastSaved.Second()),
 			Inline: false,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "Server",
+			Name:   l.T("ps2.field.server"),
 			Value:  character.World,
 			Inline: true,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "Battle Rank",
+			Name:   l.T("ps2.field.battle-rank"),
 			Value:  fmt.Sprintf("%d", character.BattleRank),
 			Inline: false,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "Kills",
+			Name:   l.T("ps2.field.kills"),
 			Value:  fmt.Sprintf("%d", character.Kills),
 			Inline: true,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "Play Time",
-			Value:  fmt.Sprintf("%0.1f (%0.1f) Hours", float32(character.PlayTime)/3600.0, float32(character.TotalPlayTimeMinutes)/60.0),
+			Name:   l.T("ps2.field.play-time"),
+			Value:  l.T("ps2.value.play-time-hours", float32(character.PlayTime)/3600.0, float32(character.TotalPlayTimeMinutes)/60.0),
 			Inline: true,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "KDR",
+			Name:   l.T("ps2.field.kdr"),
 			Value:  fmt.Sprintf("%0.2f", character.KillDeathRatio),
 			Inline: true,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "HSR",
+			Name:   l.T("ps2.field.hsr"),
 			Value:  fmt.Sprintf("%0.2f%%", character.HeadshotRatio*100),
 			Inline: true,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "KpH",
+			Name:   l.T("ps2.field.kph"),
 			Value:  fmt.Sprintf("%0.2f (%0.2f)", character.KillsPerHour, character.TotalKillsPerHour),
 			Inline: true,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "Siege Level",
+			Name:   l.T("ps2.field.siege-level"),
 			Value:  fmt.Sprintf("%0.1f", character.SiegeLevel),
 			Inline: true,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "IVI Score",
+			Name:   l.T("ps2.field.ivi-score"),
 			Value:  fmt.Sprintf("%d", character.IVIScore),
 			Inline: true,
 		},
 		&discordgo.MessageEmbedField{
-			Name:   "IVI KDR",
+			Name:   l.T("ps2.field.ivi-kdr"),
 			Value:  fmt.Sprintf("%0.2f", character.IVIKillDeathRatio),
 			Inline: true,
 		},
@@ -271,7 +271,7 @@ func (p *planetsidetwoPlugin) runCharacterStatsCommand(bot *mutterblack.Bot, cli
 		}
 
 		outfitField := &discordgo.MessageEmbedField{
-			Name:   "Outfit",
+			Name:   l.T("ps2.field.outfit"),
 			Value:  outfitValue,
 			Inline: true,
 		}
@@ -283,7 +283,7 @@ func (p *planetsidetwoPlugin) runCharacterStatsCommand(bot *mutterblack.Bot, cli
 		Author: &discordgo.MessageEmbedAuthor{
 			Name: character.Name,
 		},
-		Title: "Click here for full stats",
+		Title: l.T("ps2.full-stats"),
 		URL:   VOIDWELL_URI + "ps2/player/" + character.CharacterId,
 		Color: 0x070707,
 		Thumbnail: &discordgo.MessageEmbedThumbnail{
@@ -292,37 +292,35 @@ func (p *planetsidetwoPlugin) runCharacterStatsCommand(bot *mutterblack.Bot, cli
 		Fields: fields,
 	}
 
+	result.AddStaleFooter(embed, l)
+
 	p.RLock()
 	client.SendEmbedMessage(message.Channel(), embed)
 	p.RUnlock()
 }
 
 func (p *planetsidetwoPlugin) runCharacterWeaponStatsCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, args map[string]string, trigger string) {
-	if trigger == "ps2c-ps4us" {
-		args["platform"] = "ps4us"
-	} else if trigger == "ps2c-ps4eu" {
-		args["platform"] = "ps4eu"
-	} else {
-		args["platform"] = "pc"
-	}
+	args["platform"] = p.platform(bot, message, trigger)
+
+	l := bot.Localizer(message)
 
-	resp, err := mutterblack.SendCoreCommand("planetside2", "character-weapon", args)
+	result, err := bot.Core.CommandResult("planetside2", "character-weapon", args)
 
 	if err != nil {
 		p.RLock()
-		client.SendMessage(message.Channel(), fmt.Sprintf("%s", err))
+		client.SendMessage(message.Channel(), mutterblack.CoreErrorMessage(l, err, "ps2.unavailable"))
 		p.RUnlock()
 		return
 	}
 
 	var weapon PlanetsideCharacterWeapon
-	json.Unmarshal(resp, &weapon)
+	json.Unmarshal(result.Data, &weapon)
 
 	embed := &discordgo.MessageEmbed{
 		Author: &discordgo.MessageEmbedAuthor{
 			Name: weapon.CharacterName + " [" + weapon.WeaponName + "]",
 		},
-		Title: "Click here for full stats",
+		Title: l.T("ps2.full-stats"),
 		URL:   VOIDWELL_URI + "ps2/player/" + weapon.CharacterId,
 		Color: 0x070707,
 		Thumbnail: &discordgo.MessageEmbedThumbnail{
@@ -330,27 +328,27 @@ func (p *planetsidetwoPlugin) runCharacterWeaponStatsCommand(bot *mutterblack.Bo
 		},
 		Fields: []*discordgo.MessageEmbedField{
 			&discordgo.MessageEmbedField{
-				Name:   "Kills",
+				Name:   l.T("ps2.field.kills"),
 				Value:  fmt.Sprintf("%d", weapon.Kills),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Deaths",
+				Name:   l.T("ps2.field.deaths"),
 				Value:  fmt.Sprintf("%d", weapon.Deaths),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Play Time",
-				Value:  fmt.Sprintf("%d Minutes", weapon.PlayTime/60),
+				Name:   l.T("ps2.field.play-time"),
+				Value:  l.N("ps2.value.play-time-minutes", weapon.PlayTime/60, weapon.PlayTime/60),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Score",
+				Name:   l.T("ps2.field.score"),
 				Value:  fmt.Sprintf("%d", weapon.Score),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "KpH",
+				Name:   l.T("ps2.field.kph"),
 				Value:  fmt.Sprintf("%0.2f", weapon.KillsPerHour),
 				Inline: true,
 			},
@@ -360,7 +358,7 @@ func (p *planetsidetwoPlugin) runCharacterWeaponStatsCommand(bot *mutterblack.Bo
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "KDR",
+				Name:   l.T("ps2.field.kdr"),
 				Value:  fmt.Sprintf("%0.2f", weapon.KillDeathRatio),
 				Inline: true,
 			},
@@ -370,7 +368,7 @@ func (p *planetsidetwoPlugin) runCharacterWeaponStatsCommand(bot *mutterblack.Bo
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "HSR",
+				Name:   l.T("ps2.field.hsr"),
 				Value:  fmt.Sprintf("%0.2f%%", weapon.HeadshotRatio*100),
 				Inline: true,
 			},
@@ -380,7 +378,7 @@ func (p *planetsidetwoPlugin) runCharacterWeaponStatsCommand(bot *mutterblack.Bo
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Accuracy",
+				Name:   l.T("ps2.field.accuracy"),
 				Value:  fmt.Sprintf("%0.2f%%", weapon.Accuracy*100),
 				Inline: true,
 			},
@@ -392,37 +390,35 @@ func (p *planetsidetwoPlugin) runCharacterWeaponStatsCommand(bot *mutterblack.Bo
 		},
 	}
 
+	result.AddStaleFooter(embed, l)
+
 	p.RLock()
 	client.SendEmbedMessage(message.Channel(), embed)
 	p.RUnlock()
 }
 
 func (p *planetsidetwoPlugin) runOutfitStatsCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, args map[string]string, trigger string) {
-	if trigger == "ps2c-ps4us" {
-		args["platform"] = "ps4us"
-	} else if trigger == "ps2c-ps4eu" {
-		args["platform"] = "ps4eu"
-	} else {
-		args["platform"] = "pc"
-	}
+	args["platform"] = p.platform(bot, message, trigger)
+
+	l := bot.Localizer(message)
 
-	resp, err := mutterblack.SendCoreCommand("planetside2", "outfit", args)
+	result, err := bot.Core.CommandResult("planetside2", "outfit", args)
 
 	if err != nil {
 		p.RLock()
-		client.SendMessage(message.Channel(), fmt.Sprintf("%s", err))
+		client.SendMessage(message.Channel(), mutterblack.CoreErrorMessage(l, err, "ps2.unavailable"))
 		p.RUnlock()
 		return
 	}
 
 	var outfit PlanetsideOutfit
-	json.Unmarshal(resp, &outfit)
+	json.Unmarshal(result.Data, &outfit)
 
 	embed := &discordgo.MessageEmbed{
 		Author: &discordgo.MessageEmbedAuthor{
 			Name: "[" + outfit.Alias + "] " + outfit.Name,
 		},
-		Title: "Click here for full stats",
+		Title: l.T("ps2.full-stats"),
 		URL:   VOIDWELL_URI + "ps2/outfit/" + outfit.OutfitId,
 		Color: 0x070707,
 		Thumbnail: &discordgo.MessageEmbedThumbnail{
@@ -430,38 +426,40 @@ func (p *planetsidetwoPlugin) runOutfitStatsCommand(bot *mutterblack.Bot, client
 		},
 		Fields: []*discordgo.MessageEmbedField{
 			&discordgo.MessageEmbedField{
-				Name:   "Server",
+				Name:   l.T("ps2.field.server"),
 				Value:  outfit.WorldName,
 				Inline: false,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Leader",
+				Name:   l.T("ps2.field.leader"),
 				Value:  outfit.LeaderName,
 				Inline: false,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Member Count",
+				Name:   l.T("ps2.field.member-count"),
 				Value:  fmt.Sprintf("%d", outfit.MemberCount),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Activity 7 Days",
+				Name:   l.T("ps2.field.activity-7"),
 				Value:  fmt.Sprintf("%d", outfit.Activity7Days),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Activity 30 Days",
+				Name:   l.T("ps2.field.activity-30"),
 				Value:  fmt.Sprintf("%d", outfit.Activity30Days),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Activity 90 Days",
+				Name:   l.T("ps2.field.activity-90"),
 				Value:  fmt.Sprintf("%d", outfit.Activity90Days),
 				Inline: true,
 			},
 		},
 	}
 
+	result.AddStaleFooter(embed, l)
+
 	p.RLock()
 	client.SendEmbedMessage(message.Channel(), embed)
 	p.RUnlock()
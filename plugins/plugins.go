@@ -0,0 +1,35 @@
+// Package plugins wires up the plugins the bot runs with, so the bot and its tools register the same set.
+package plugins
+
+import (
+	"github.com/lampjaw/mutterblack.discord"
+	"github.com/lampjaw/mutterblack.discord/plugins/inviteplugin"
+	"github.com/lampjaw/mutterblack.discord/plugins/planetsidetwoplugin"
+	"github.com/lampjaw/mutterblack.discord/plugins/remoteplugin"
+	"github.com/lampjaw/mutterblack.discord/plugins/statsplugin"
+	"github.com/lampjaw/mutterblack.discord/plugins/uwutranslatorplugin"
+	"github.com/lampjaw/mutterblack.discord/plugins/weatherplugin"
+)
+
+// AddCommands adds the simple commands that are handled by the command plugin.
+func AddCommands(commandPlugin *mutterblack.CommandPlugin) {
+	commandPlugin.AddCommand("invite", inviteplugin.InviteCommand, inviteplugin.InviteHelp)
+	commandPlugin.AddCommand("join", inviteplugin.InviteCommand, nil)
+	commandPlugin.AddCommand("stats", statsplugin.StatsCommand, statsplugin.StatsHelp)
+	commandPlugin.AddCommand("info", statsplugin.StatsCommand, nil)
+	commandPlugin.AddCommand("stat", statsplugin.StatsCommand, nil)
+}
+
+// New returns every other plugin the bot runs with, including the remote plugins listed in REMOTE_PLUGINS.
+func New() []mutterblack.Plugin {
+	return append([]mutterblack.Plugin{
+		mutterblack.NewHelpPlugin(),
+		mutterblack.NewLocalePlugin(),
+		mutterblack.NewMaintenancePlugin(),
+		mutterblack.NewConfigurePlugin(),
+		mutterblack.NewDataPlugin(),
+		weatherplugin.New(),
+		planetsidetwoplugin.New(),
+		uwutranslatorplugin.New(),
+	}, remoteplugin.FromEnvironment()...)
+}
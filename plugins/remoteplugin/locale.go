@@ -0,0 +1,25 @@
+package remoteplugin
+
+import (
+	"github.com/lampjaw/mutterblack.discord"
+)
+
+func init() {
+	mutterblack.RegisterCatalog("en", mutterblack.Catalog{
+		"remote.unavailable":       "%s is unavailable right now, please try again later.",
+		"remote.error":             "%s failed to answer, please try again later.",
+		"remote.stats.healthy":     "up",
+		"remote.stats.unhealthy":   "down (%s)",
+		"remote.stats.calls#one":   "%s, %d call, %d failed",
+		"remote.stats.calls#other": "%s, %d calls, %d failed",
+	})
+
+	mutterblack.RegisterCatalog("es", mutterblack.Catalog{
+		"remote.unavailable":       "%s no está disponible ahora mismo, inténtalo de nuevo más tarde.",
+		"remote.error":             "%s no respondió, inténtalo de nuevo más tarde.",
+		"remote.stats.healthy":     "activo",
+		"remote.stats.unhealthy":   "caído (%s)",
+		"remote.stats.calls#one":   "%s, %d llamada, %d fallida",
+		"remote.stats.calls#other": "%s, %d llamadas, %d fallidas",
+	})
+}
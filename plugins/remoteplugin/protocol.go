@@ -0,0 +1,123 @@
+package remoteplugin
+
+import (
+	"encoding/json"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// The protocol is JSON-RPC 2.0, every request is POSTed to the plugin's URL and answered in the response body.
+const (
+	// MethodDescribe asks the plugin for its commands, it takes no params and returns a Description.
+	MethodDescribe = "plugin.describe"
+	// MethodHealth checks the plugin is up, it takes no params and returns a Health.
+	MethodHealth = "plugin.health"
+	// MethodCommand runs one of the plugin's commands, it takes an Invocation and returns a Result.
+	MethodCommand = "plugin.command"
+	// MethodMessage passes every message to plugins that listen for them, it takes an Invocation and returns a Result.
+	MethodMessage = "plugin.message"
+)
+
+// Error codes returned by the plugin, as defined by JSON-RPC 2.0.
+const (
+	ErrorParse          = -32700
+	ErrorInvalidRequest = -32600
+	ErrorMethodNotFound = -32601
+	ErrorInvalidParams  = -32602
+	ErrorInternal       = -32603
+)
+
+// Request is a JSON-RPC request sent to the plugin.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response returned by the plugin, with either Result or Error set.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Description is the result of MethodDescribe.
+type Description struct {
+	Commands []Command `json:"commands"`
+	// Messages is true if the plugin wants every message through MethodMessage.
+	Messages bool `json:"messages,omitempty"`
+}
+
+// Command describes one command of the plugin, it is turned into a mutterblack.CommandDefinition.
+type Command struct {
+	ID          string     `json:"id"`
+	Triggers    []string   `json:"triggers"`
+	Description string     `json:"description"`
+	Arguments   []Argument `json:"arguments,omitempty"`
+	Examples    []string   `json:"examples,omitempty"`
+	// Permissions is the set of Discord permission bits the caller needs in the channel.
+	Permissions     int  `json:"permissions,omitempty"`
+	ModeratorOnly   bool `json:"moderatorOnly,omitempty"`
+	OwnerOnly       bool `json:"ownerOnly,omitempty"`
+	CooldownSeconds int  `json:"cooldownSeconds,omitempty"`
+}
+
+// Argument describes one argument of a command, Pattern is a regular expression matching its value.
+type Argument struct {
+	Alias       string `json:"alias"`
+	Pattern     string `json:"pattern"`
+	Optional    bool   `json:"optional,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Health is the result of MethodHealth.
+type Health struct {
+	OK     bool   `json:"ok"`
+	Status string `json:"status,omitempty"`
+}
+
+// Invocation is the params of MethodCommand and MethodMessage, Command, Trigger and Arguments are only set for commands.
+type Invocation struct {
+	Command   string            `json:"command,omitempty"`
+	Trigger   string            `json:"trigger,omitempty"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+	Message   MessageInfo       `json:"message"`
+}
+
+// MessageInfo describes the message that invoked the plugin.
+type MessageInfo struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channelId"`
+	GuildID   string `json:"guildId,omitempty"`
+	UserID    string `json:"userId"`
+	UserName  string `json:"userName"`
+	Content   string `json:"content"`
+	// Locale is the locale the user reads replies in.
+	Locale string `json:"locale"`
+}
+
+// Result is the result of MethodCommand and MethodMessage.
+type Result struct {
+	Replies []Reply `json:"replies,omitempty"`
+}
+
+// Reply is a message sent on behalf of the plugin, it has Content, an Embed or both.
+type Reply struct {
+	Content string                  `json:"content,omitempty"`
+	Embed   *discordgo.MessageEmbed `json:"embed,omitempty"`
+	// Private sends the reply to the user directly instead of the channel.
+	Private bool `json:"private,omitempty"`
+}
@@ -0,0 +1,435 @@
+// Package remoteplugin runs plugins in another process, talking to them with JSON-RPC over HTTP.
+//
+// The process describes its commands with plugin.describe and is called with plugin.command when one is used,
+// see protocol.go for every method. cmd/remoteplugin-stub is a small example of such a process.
+package remoteplugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lampjaw/mutterblack.discord"
+)
+
+const (
+	defaultTimeout        = 5 * time.Second
+	defaultHealthInterval = 30 * time.Second
+	// maxResponseSize is the largest response body read from a plugin.
+	maxResponseSize = 1 << 20
+)
+
+// Config describes where a remote plugin runs.
+type Config struct {
+	// Name is the plugin name used for help, configuration and saved data.
+	Name string
+	// URL is the address every JSON-RPC request is POSTed to.
+	URL string
+	// Timeout is the longest a single call may take, defaults to 5 seconds.
+	Timeout time.Duration
+	// HealthInterval is the time between two health checks, defaults to 30 seconds.
+	HealthInterval time.Duration
+}
+
+type remotePlugin struct {
+	mutterblack.BasePlugin
+	sync.RWMutex
+
+	url            string
+	client         *http.Client
+	healthInterval time.Duration
+	requestID      int64
+
+	commands  []mutterblack.CommandDefinition
+	messages  bool
+	healthy   bool
+	lastError string
+	calls     int
+	failures  int
+
+	stop chan struct{}
+}
+
+// New returns a plugin that proxies to the process described by config.
+func New(config Config) mutterblack.Plugin {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.HealthInterval <= 0 {
+		config.HealthInterval = defaultHealthInterval
+	}
+
+	return &remotePlugin{
+		BasePlugin:     mutterblack.NewBasePlugin(config.Name),
+		url:            config.URL,
+		client:         &http.Client{Timeout: config.Timeout},
+		healthInterval: config.HealthInterval,
+	}
+}
+
+// FromEnvironment returns a plugin for every entry of REMOTE_PLUGINS, a comma separated list of name=url pairs.
+// REMOTE_PLUGIN_TIMEOUT sets the timeout of every call in seconds.
+func FromEnvironment() []mutterblack.Plugin {
+	plugins := []mutterblack.Plugin{}
+
+	timeout := time.Duration(0)
+	if seconds, err := strconv.Atoi(os.Getenv("REMOTE_PLUGIN_TIMEOUT")); err == nil {
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	for _, entry := range strings.Split(os.Getenv("REMOTE_PLUGINS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			log.Printf("Ignoring remote plugin %q, expected name=url", entry)
+			continue
+		}
+
+		plugins = append(plugins, New(Config{
+			Name:    strings.TrimSpace(parts[0]),
+			URL:     strings.TrimSpace(parts[1]),
+			Timeout: timeout,
+		}))
+	}
+
+	return plugins
+}
+
+// Commands returns the commands the plugin described, or nil until it has been reached.
+func (p *remotePlugin) Commands() []mutterblack.CommandDefinition {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.commands
+}
+
+// Ready starts checking the health of the plugin, its commands are described on the first successful check.
+func (p *remotePlugin) Ready(bot *mutterblack.Bot, client *mutterblack.Discord) error {
+	p.Lock()
+	stop := make(chan struct{})
+	p.stop = stop
+	p.Unlock()
+
+	bot.Go(p, "HealthMonitor", func() { p.monitor(stop) })
+	return nil
+}
+
+// Shutdown stops the health checks.
+func (p *remotePlugin) Shutdown(bot *mutterblack.Bot, client *mutterblack.Discord) error {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+	return nil
+}
+
+func (p *remotePlugin) monitor(stop chan struct{}) {
+	p.check()
+
+	t := time.NewTicker(p.healthInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			p.check()
+		}
+	}
+}
+
+// check calls plugin.health and describes the plugin again whenever it comes back up, its commands may have changed.
+func (p *remotePlugin) check() {
+	health := Health{}
+	err := p.call(MethodHealth, nil, &health)
+	if err == nil && !health.OK {
+		err = fmt.Errorf("unhealthy: %s", health.Status)
+	}
+
+	if err == nil {
+		p.RLock()
+		healthy := p.healthy
+		p.RUnlock()
+
+		if !healthy {
+			err = p.describe()
+		}
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if err != nil {
+		if p.healthy || p.lastError == "" {
+			log.Printf("Remote plugin %s is unavailable: %v", p.Name(), err)
+		}
+		p.healthy = false
+		p.lastError = err.Error()
+		return
+	}
+
+	if !p.healthy {
+		log.Printf("Remote plugin %s is available with %d commands", p.Name(), len(p.commands))
+	}
+	p.healthy = true
+	p.lastError = ""
+}
+
+func (p *remotePlugin) describe() error {
+	description := Description{}
+	if err := p.call(MethodDescribe, nil, &description); err != nil {
+		return err
+	}
+
+	commands := []mutterblack.CommandDefinition{}
+	for _, command := range description.Commands {
+		commandDefinition, err := p.commandDefinition(command)
+		if err != nil {
+			log.Printf("Ignoring command %q of remote plugin %s: %v", command.ID, p.Name(), err)
+			continue
+		}
+		commands = append(commands, commandDefinition)
+	}
+
+	p.Lock()
+	p.commands = commands
+	p.messages = description.Messages
+	p.Unlock()
+
+	return nil
+}
+
+var aliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// commandDefinition converts a described command, rejecting anything that would break command matching.
+func (p *remotePlugin) commandDefinition(command Command) (mutterblack.CommandDefinition, error) {
+	if command.ID == "" {
+		return mutterblack.CommandDefinition{}, fmt.Errorf("missing id")
+	}
+	if len(command.Triggers) == 0 {
+		return mutterblack.CommandDefinition{}, fmt.Errorf("missing triggers")
+	}
+	for _, trigger := range command.Triggers {
+		if trigger == "" || strings.ContainsAny(trigger, " \t\n") {
+			return mutterblack.CommandDefinition{}, fmt.Errorf("invalid trigger %q", trigger)
+		}
+	}
+
+	var arguments []mutterblack.CommandDefinitionArgument
+	for _, argument := range command.Arguments {
+		if !aliasPattern.MatchString(argument.Alias) {
+			return mutterblack.CommandDefinition{}, fmt.Errorf("invalid argument alias %q", argument.Alias)
+		}
+		if _, err := regexp.Compile(fmt.Sprintf("(?P<%s>%s)", argument.Alias, argument.Pattern)); err != nil {
+			return mutterblack.CommandDefinition{}, fmt.Errorf("invalid pattern for %s: %v", argument.Alias, err)
+		}
+
+		arguments = append(arguments, mutterblack.CommandDefinitionArgument{
+			Alias:       argument.Alias,
+			Pattern:     argument.Pattern,
+			Optional:    argument.Optional,
+			Type:        mutterblack.ArgumentType(argument.Type),
+			Description: argument.Description,
+		})
+	}
+
+	return mutterblack.CommandDefinition{
+		CommandGroup:  p.Name(),
+		CommandID:     command.ID,
+		Triggers:      command.Triggers,
+		Arguments:     arguments,
+		Description:   command.Description,
+		Examples:      command.Examples,
+		Permissions:   command.Permissions,
+		ModeratorOnly: command.ModeratorOnly,
+		OwnerOnly:     command.OwnerOnly,
+		Cooldown:      time.Duration(command.CooldownSeconds) * time.Second,
+		Callback: func(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, args map[string]string, trigger string) {
+			p.runCommand(bot, client, message, command.ID, args, trigger)
+		},
+	}, nil
+}
+
+func (p *remotePlugin) runCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, commandID string, args map[string]string, trigger string) {
+	l := bot.Localizer(message)
+
+	p.RLock()
+	healthy := p.healthy
+	p.RUnlock()
+
+	if !healthy {
+		client.SendMessage(message.Channel(), l.T("remote.unavailable", p.Name()))
+		return
+	}
+
+	client.Typing(message.Channel())
+
+	result := Result{}
+	err := p.call(MethodCommand, Invocation{
+		Command:   commandID,
+		Trigger:   trigger,
+		Arguments: args,
+		Message:   messageInfo(client, message, l),
+	}, &result)
+	if err != nil {
+		log.Printf("Error calling remote plugin %s command %s: %v", p.Name(), commandID, err)
+		client.SendMessage(message.Channel(), l.T("remote.error", p.Name()))
+		return
+	}
+
+	p.reply(client, message, result)
+}
+
+// Priority makes remote plugins see messages after local ones, so a message handler can keep messages from reaching the process.
+func (p *remotePlugin) Priority() int {
+	return -1
+}
+
+// Message passes new messages to the plugin if it asked for them, failures are only logged.
+func (p *remotePlugin) Message(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) {
+	if message.Type() != mutterblack.MessageTypeCreate || client.IsMe(message) {
+		return
+	}
+
+	p.RLock()
+	listening := p.healthy && p.messages
+	p.RUnlock()
+
+	if !listening {
+		return
+	}
+
+	result := Result{}
+	if err := p.call(MethodMessage, Invocation{Message: messageInfo(client, message, bot.Localizer(message))}, &result); err != nil {
+		log.Printf("Error passing message to remote plugin %s: %v", p.Name(), err)
+		return
+	}
+
+	p.reply(client, message, result)
+}
+
+func (p *remotePlugin) reply(client *mutterblack.Discord, message mutterblack.Message, result Result) {
+	for _, reply := range result.Replies {
+		channel := message.Channel()
+		if reply.Private {
+			privateChannel, err := client.PrivateChannelID(message.UserID())
+			if err != nil {
+				log.Println("Error creating private channel", err)
+				continue
+			}
+			channel = privateChannel
+		}
+
+		if reply.Content != "" {
+			client.SendMessage(channel, reply.Content)
+		}
+		if reply.Embed != nil {
+			client.SendEmbedMessage(channel, reply.Embed)
+		}
+	}
+}
+
+// Stats reports whether the plugin can be reached and how many calls to it failed.
+func (p *remotePlugin) Stats(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) []string {
+	l := bot.Localizer(message)
+
+	p.RLock()
+	defer p.RUnlock()
+
+	status := l.T("remote.stats.healthy")
+	if !p.healthy {
+		status = l.T("remote.stats.unhealthy", p.lastError)
+	}
+
+	return []string{
+		fmt.Sprintf("%s: \t%s\n", p.Name(), l.N("remote.stats.calls", p.calls, status, p.calls, p.failures)),
+	}
+}
+
+// call sends a JSON-RPC request and decodes its result into result.
+func (p *remotePlugin) call(method string, params interface{}, result interface{}) (err error) {
+	defer func() {
+		p.Lock()
+		p.calls++
+		if err != nil {
+			p.failures++
+		}
+		p.Unlock()
+	}()
+
+	request := Request{
+		JSONRPC: "2.0",
+		ID:      atomic.AddInt64(&p.requestID, 1),
+		Method:  method,
+	}
+	if params != nil {
+		if request.Params, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Post(p.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseSize))
+		return fmt.Errorf("%s returned %s", method, resp.Status)
+	}
+
+	response := Response{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&response); err != nil {
+		return fmt.Errorf("%s returned an invalid response: %v", method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s failed: %v (%d)", method, response.Error, response.Error.Code)
+	}
+	if response.ID != request.ID {
+		return fmt.Errorf("%s returned id %d, expected %d", method, response.ID, request.ID)
+	}
+
+	if result != nil && len(response.Result) > 0 {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("%s returned an invalid result: %v", method, err)
+		}
+	}
+	return nil
+}
+
+func messageInfo(client *mutterblack.Discord, message mutterblack.Message, l *mutterblack.Localizer) MessageInfo {
+	return MessageInfo{
+		ID:        message.MessageID(),
+		ChannelID: message.Channel(),
+		GuildID:   client.ChannelGuildID(message.Channel()),
+		UserID:    message.UserID(),
+		UserName:  message.UserName(),
+		Content:   message.Message(),
+		Locale:    l.Locale,
+	}
+}
@@ -0,0 +1,100 @@
+package remoteplugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// startStub builds and starts cmd/remoteplugin-stub, returning its URL and a function that stops it.
+func startStub(t *testing.T) (string, func()) {
+	if testing.Short() {
+		t.Skip("builds and starts cmd/remoteplugin-stub")
+	}
+
+	dir, err := ioutil.TempDir("", "remoteplugin-stub")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binary := filepath.Join(dir, "remoteplugin-stub")
+	build := exec.Command("go", "build", "-o", binary, "github.com/lampjaw/mutterblack.discord/cmd/remoteplugin-stub")
+	if output, err := build.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("building the stub: %v\n%s", err, output)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	stub := exec.Command(binary, "-addr", addr)
+	if err := stub.Start(); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	stop := func() {
+		stub.Process.Kill()
+		stub.Wait()
+		os.RemoveAll(dir)
+	}
+
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(50 * time.Millisecond) {
+		if conn, err := net.Dial("tcp", addr); err == nil {
+			conn.Close()
+			return fmt.Sprintf("http://%s/", addr), stop
+		}
+	}
+
+	stop()
+	t.Fatalf("the stub didn't listen on %s", addr)
+	return "", nil
+}
+
+func TestStubRoundTrip(t *testing.T) {
+	url, stop := startStub(t)
+	defer stop()
+
+	p := New(Config{Name: "Stub", URL: url}).(*remotePlugin)
+
+	p.check()
+	if !p.healthy {
+		t.Fatalf("health check failed: %s", p.lastError)
+	}
+
+	commands := map[string]bool{}
+	for _, command := range p.Commands() {
+		commands[command.CommandID] = true
+	}
+	if !commands["stub-roll"] || !commands["stub-echo"] {
+		t.Fatalf("got commands %v, want stub-roll and stub-echo", commands)
+	}
+
+	result := Result{}
+	err := p.call(MethodCommand, Invocation{
+		Command:   "stub-echo",
+		Trigger:   "echo",
+		Arguments: map[string]string{"text": "hello"},
+		Message:   MessageInfo{UserName: "tester"},
+	}, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Replies) != 1 || result.Replies[0].Embed == nil {
+		t.Fatalf("got replies %+v, want one embed", result.Replies)
+	}
+	if embed := result.Replies[0].Embed; embed.Description != "hello" || embed.Author == nil || embed.Author.Name != "tester" {
+		t.Errorf("got embed %q by %+v, want %q by tester", embed.Description, embed.Author, "hello")
+	}
+}
@@ -0,0 +1,59 @@
+package statsplugin
+
+import (
+	"github.com/lampjaw/mutterblack.discord"
+)
+
+func init() {
+	mutterblack.RegisterCatalog("en", mutterblack.Catalog{
+		"stats.help":                   "Lists bot statistics.",
+		"stats.uptime":                 "Uptime",
+		"stats.memory":                 "Memory used",
+		"stats.memory-value":           "%s / %s (%s garbage collected)",
+		"stats.tasks":                  "Concurrent tasks",
+		"stats.servers":                "Connected servers",
+		"stats.shards":                 "Shards",
+		"stats.shards-connected#one":   "%d (%d connected)",
+		"stats.shards-connected#other": "%d (%d connected)",
+		"stats.current-shard":          "Current shard",
+		"stats.last-save":              "Last save",
+		"stats.last-save-value":        "%s ago",
+		"stats.last-save-never":        "never",
+		"stats.last-save-failed":       "failed %s ago: %v",
+		"stats.save-pending#one":       "(%d plugin waiting)",
+		"stats.save-pending#other":     "(%d plugins waiting)",
+		"stats.core-group":             "Core %s",
+		"stats.core-closed":            "available",
+		"stats.core-failing#one":       "available, %d failed request",
+		"stats.core-failing#other":     "available, %d failed requests",
+		"stats.core-open#one":          "unavailable after %d failed request, retrying in %s",
+		"stats.core-open#other":        "unavailable after %d failed requests, retrying in %s",
+		"stats.core-half-open":         "recovering",
+	})
+
+	mutterblack.RegisterCatalog("es", mutterblack.Catalog{
+		"stats.help":                   "Muestra las estadísticas del bot.",
+		"stats.uptime":                 "Tiempo activo",
+		"stats.memory":                 "Memoria usada",
+		"stats.memory-value":           "%s / %s (%s recolectados)",
+		"stats.tasks":                  "Tareas concurrentes",
+		"stats.servers":                "Servidores conectados",
+		"stats.shards":                 "Shards",
+		"stats.shards-connected#one":   "%d (%d conectado)",
+		"stats.shards-connected#other": "%d (%d conectados)",
+		"stats.current-shard":          "Shard actual",
+		"stats.last-save":              "Último guardado",
+		"stats.last-save-value":        "hace %s",
+		"stats.last-save-never":        "nunca",
+		"stats.last-save-failed":       "falló hace %s: %v",
+		"stats.save-pending#one":       "(%d plugin pendiente)",
+		"stats.save-pending#other":     "(%d plugins pendientes)",
+		"stats.core-group":             "Core %s",
+		"stats.core-closed":            "disponible",
+		"stats.core-failing#one":       "disponible, %d petición fallida",
+		"stats.core-failing#other":     "disponible, %d peticiones fallidas",
+		"stats.core-open#one":          "no disponible tras %d petición fallida, reintentando en %s",
+		"stats.core-open#other":        "no disponible tras %d peticiones fallidas, reintentando en %s",
+		"stats.core-half-open":         "recuperándose",
+	})
+}
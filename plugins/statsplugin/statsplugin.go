@@ -25,6 +25,39 @@ func getDurationString(duration time.Duration) string {
 	)
 }
 
+// saveStatusString describes when plugin data was last saved, whether it failed and how many plugins wait to be saved.
+func saveStatusString(l *mutterblack.Localizer, status mutterblack.SaveStatus) string {
+	out := l.T("stats.last-save-never")
+	if status.Err != nil {
+		out = l.T("stats.last-save-failed", getDurationString(time.Now().Sub(status.Last)), status.Err)
+	} else if !status.Last.IsZero() {
+		out = l.T("stats.last-save-value", getDurationString(time.Now().Sub(status.Last)))
+	}
+
+	if status.Pending > 0 {
+		out += " " + l.N("stats.save-pending", status.Pending, status.Pending)
+	}
+	return out
+}
+
+// coreStatusString describes the circuit breaker of a core command group.
+func coreStatusString(l *mutterblack.Localizer, status mutterblack.CoreGroupStatus) string {
+	switch status.State {
+	case mutterblack.BreakerOpen:
+		wait := time.Until(status.RetryAt)
+		if wait < 0 {
+			wait = 0
+		}
+		return l.N("stats.core-open", status.Failures, status.Failures, getDurationString(wait))
+	case mutterblack.BreakerHalfOpen:
+		return l.T("stats.core-half-open")
+	}
+	if status.Failures > 0 {
+		return l.N("stats.core-failing", status.Failures, status.Failures)
+	}
+	return l.T("stats.core-closed")
+}
+
 // StatsCommand returns bot statistics.
 func StatsCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, command string, parts []string) {
 	stats := runtime.MemStats{}
@@ -33,16 +66,22 @@ func StatsCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mut
 	w := &tabwriter.Writer{}
 	buf := &bytes.Buffer{}
 
+	l := bot.Localizer(message)
+
 	w.Init(buf, 0, 4, 0, ' ', 0)
 	fmt.Fprintf(w, "```\n")
 	fmt.Fprintf(w, "mutterblack: \t%s\n", mutterblack.VersionString)
 	fmt.Fprintf(w, "Discordgo: \t%s\n", discordgo.VERSION)
 	fmt.Fprintf(w, "Go: \t%s\n", runtime.Version())
-	fmt.Fprintf(w, "Uptime: \t%s\n", getDurationString(time.Now().Sub(statsStartTime)))
-	fmt.Fprintf(w, "Memory used: \t%s / %s (%s garbage collected)\n", humanize.Bytes(stats.Alloc), humanize.Bytes(stats.Sys), humanize.Bytes(stats.TotalAlloc))
-	fmt.Fprintf(w, "Concurrent tasks: \t%d\n", runtime.NumGoroutine())
+	fmt.Fprintf(w, "%s: \t%s\n", l.T("stats.uptime"), getDurationString(time.Now().Sub(statsStartTime)))
+	fmt.Fprintf(w, "%s: \t%s\n", l.T("stats.memory"), l.T("stats.memory-value", humanize.Bytes(stats.Alloc), humanize.Bytes(stats.Sys), humanize.Bytes(stats.TotalAlloc)))
+	fmt.Fprintf(w, "%s: \t%d\n", l.T("stats.tasks"), runtime.NumGoroutine())
 
-	fmt.Fprintf(w, "Connected servers: \t%d\n", client.ChannelCount())
+	fmt.Fprintf(w, "%s: \t%s\n", l.T("stats.last-save"), saveStatusString(l, bot.SaveStatus()))
+	for _, status := range bot.Core.GroupStatus() {
+		fmt.Fprintf(w, "%s: \t%s\n", l.T("stats.core-group", status.Group), coreStatusString(l, status))
+	}
+	fmt.Fprintf(w, "%s: \t%d\n", l.T("stats.servers"), client.ChannelCount())
 	if len(client.Sessions) > 1 {
 		shards := 0
 		for _, s := range client.Sessions {
@@ -51,15 +90,15 @@ func StatsCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mut
 			}
 		}
 		if shards == len(client.Sessions) {
-			fmt.Fprintf(w, "Shards: \t%d\n", shards)
+			fmt.Fprintf(w, "%s: \t%d\n", l.T("stats.shards"), shards)
 		} else {
-			fmt.Fprintf(w, "Shards: \t%d (%d connected)\n", len(client.Sessions), shards)
+			fmt.Fprintf(w, "%s: \t%s\n", l.T("stats.shards"), l.N("stats.shards-connected", shards, len(client.Sessions), shards))
 		}
 		guild, err := client.Channel(message.Channel())
 		if err == nil {
 			id, err := strconv.Atoi(guild.ID)
 			if err == nil {
-				fmt.Fprintf(w, "Current shard: \t%d\n", ((id>>22)%len(client.Sessions) + 1))
+				fmt.Fprintf(w, "%s: \t%d\n", l.T("stats.current-shard"), ((id>>22)%len(client.Sessions) + 1))
 			}
 		}
 	}
@@ -72,9 +111,10 @@ func StatsCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mut
 	}
 
 	for _, name := range names {
-		stats := plugins[name].Stats(bot, client, message)
-		for _, stat := range stats {
-			fmt.Fprint(w, stat)
+		if provider, ok := plugins[name].(mutterblack.StatsProvider); ok {
+			for _, stat := range provider.Stats(bot, client, message) {
+				fmt.Fprint(w, stat)
+			}
 		}
 	}
 
@@ -92,4 +132,4 @@ func StatsCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mut
 }
 
 // StatsHelp is the help for the stats command.
-var StatsHelp = mutterblack.NewCommandHelp("", "Lists bot statistics.")
+var StatsHelp = mutterblack.NewCommandHelp("", "stats.help")
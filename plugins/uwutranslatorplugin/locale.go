@@ -0,0 +1,23 @@
+package uwutranslatorplugin
+
+import (
+	"github.com/lampjaw/mutterblack.discord"
+)
+
+func init() {
+	mutterblack.RegisterCatalog("en", mutterblack.Catalog{
+		"uwu.command.translate":              "Translate the previous message UwU.",
+		"uwu.unavailable":                    "Translations are temporarily unavailable, please try again in a few minutes.",
+		"uwu.no-message":                     "Unable to find a message to translate.",
+		"uwu.footer":                         "in #%s at %s",
+		"uwu.setting.auto-translate-channel": "Every message sent in this channel is translated.",
+	})
+
+	mutterblack.RegisterCatalog("es", mutterblack.Catalog{
+		"uwu.command.translate":              "Traduce el mensaje anterior UwU.",
+		"uwu.unavailable":                    "Las traducciones no están disponibles temporalmente, inténtalo de nuevo en unos minutos.",
+		"uwu.no-message":                     "No se encontró ningún mensaje para traducir.",
+		"uwu.footer":                         "en #%s de %s",
+		"uwu.setting.auto-translate-channel": "Todos los mensajes enviados en este canal se traducen.",
+	})
+}
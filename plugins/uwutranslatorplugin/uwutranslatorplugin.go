@@ -2,8 +2,8 @@ package uwutranslatorplugin
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
+	"strings"
 	"sync"
 
 	"github.com/bwmarrin/discordgo"
@@ -11,6 +11,7 @@ import (
 )
 
 type uwutranslatorPlugin struct {
+	mutterblack.BasePlugin
 	sync.RWMutex
 }
 
@@ -23,61 +24,43 @@ func (p *uwutranslatorPlugin) Commands() []mutterblack.CommandDefinition {
 				"twanswate",
 			},
 			Arguments:   nil,
-			Description: "Translate the previous message UwU.",
+			Description: "uwu.command.translate",
 			Callback:    p.runTranslateCommand,
 		},
 	}
 }
 
-func (p *uwutranslatorPlugin) Name() string {
-	return "uwuTranslator"
-}
-
-func (p *uwutranslatorPlugin) Load(bot *mutterblack.Bot, client *mutterblack.Discord, data []byte) error {
-	if data != nil {
-		if err := json.Unmarshal(data, p); err != nil {
-			log.Println("Error loading data", err)
-		}
-	}
-
-	return nil
-}
-
-func (p *uwutranslatorPlugin) Save() ([]byte, error) {
-	return json.Marshal(p)
-}
-
-func (p *uwutranslatorPlugin) Help(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, detailed bool) []string {
-	return []string{
-		mutterblack.CommandHelp(client, "twanswate", "", "Translate the previous message UwU.")[0],
+func (p *uwutranslatorPlugin) Settings() []mutterblack.Setting {
+	return []mutterblack.Setting{
+		mutterblack.Setting{
+			Key:         "autoTranslateChannel",
+			Type:        mutterblack.SettingTypeChannel,
+			Description: "uwu.setting.auto-translate-channel",
+		},
 	}
 }
 
-func (p *uwutranslatorPlugin) Message(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) {
-
-}
-
-func (p *uwutranslatorPlugin) Stats(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) []string {
-	return nil
-}
-
 func New() mutterblack.Plugin {
-	return &uwutranslatorPlugin{}
+	return &uwutranslatorPlugin{
+		BasePlugin: mutterblack.NewBasePlugin("uwuTranslator"),
+	}
 }
 
 func (p *uwutranslatorPlugin) runTranslateCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, args map[string]string, trigger string) {
+	l := bot.Localizer(message)
+
 	previousMessages, err := client.GetMessages(message.Channel(), 1, message.MessageID())
 
 	if err != nil {
 		p.RLock()
-		client.SendMessage(message.Channel(), fmt.Sprintf("%s", err))
+		client.SendMessage(message.Channel(), l.T(err.Error()))
 		p.RUnlock()
 		return
 	}
 
 	if previousMessages == nil || len(previousMessages) == 0 {
 		p.RLock()
-		client.SendMessage(message.Channel(), "Unable to find a message to translate.")
+		client.SendMessage(message.Channel(), l.T("uwu.no-message"))
 		p.RUnlock()
 		return
 	}
@@ -88,40 +71,67 @@ func (p *uwutranslatorPlugin) runTranslateCommand(bot *mutterblack.Bot, client *
 		return
 	}
 
+	if err := p.translate(bot, client, message.Channel(), previousMessage, l); err != nil {
+		p.RLock()
+		client.SendMessage(message.Channel(), mutterblack.CoreErrorMessage(l, err, "uwu.unavailable"))
+		p.RUnlock()
+	}
+}
+
+// Message translates every message sent in the server's auto translate channel.
+func (p *uwutranslatorPlugin) Message(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) {
+	if message.Type() != mutterblack.MessageTypeCreate || client.IsMe(message) || client.IsPrivate(message) || message.Message() == "" {
+		return
+	}
+
+	if strings.HasPrefix(message.Message(), client.MessageCommandPrefix(message)) {
+		return
+	}
+
+	if bot.Settings(p, message).String("autoTranslateChannel") != message.Channel() {
+		return
+	}
+
+	if err := p.translate(bot, client, message.Channel(), message, bot.Localizer(message)); err != nil {
+		log.Printf("Error auto translating message in %s: %v", message.Channel(), err)
+	}
+}
+
+// translate sends the translation of source to channel.
+func (p *uwutranslatorPlugin) translate(bot *mutterblack.Bot, client *mutterblack.Discord, channelID string, source mutterblack.Message, l *mutterblack.Localizer) error {
 	textArg := make(map[string]string)
-	textArg["text"] = previousMessage.Message()
+	textArg["text"] = source.Message()
 
-	resp, err := mutterblack.SendCoreCommand("uwutranslator", "translate", textArg)
+	resp, err := bot.Core.Command("uwutranslator", "translate", textArg)
 
 	if err != nil {
-		p.RLock()
-		client.SendMessage(message.Channel(), fmt.Sprintf("%s", err))
-		p.RUnlock()
-		return
+		return err
 	}
 
-	channel, err := client.Channel(message.Channel())
+	channel, err := client.Channel(channelID)
 	guild, err := client.Guild(channel.GuildID)
 
 	var translatedText string
 	json.Unmarshal(resp, &translatedText)
 
-	timestamp, err := previousMessage.Timestamp()
+	timestamp, err := source.Timestamp()
 
 	embed := &discordgo.MessageEmbed{
 		Author: &discordgo.MessageEmbedAuthor{
-			Name:    previousMessage.UserName(),
-			IconURL: previousMessage.UserAvatar(),
+			Name:    source.UserName(),
+			IconURL: source.UserAvatar(),
 		},
 		Color:       0x070707,
 		Description: translatedText,
 		Timestamp:   timestamp.UTC().Format("2006-01-02T15:04:05-0700"),
 		Footer: &discordgo.MessageEmbedFooter{
-			Text: fmt.Sprintf("in #%s at %s", channel.Name, guild.Name),
+			Text: l.T("uwu.footer", channel.Name, guild.Name),
 		},
 	}
 
 	p.RLock()
-	client.SendEmbedMessage(message.Channel(), embed)
+	client.SendEmbedMessage(channelID, embed)
 	p.RUnlock()
+
+	return nil
 }
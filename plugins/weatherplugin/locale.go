@@ -0,0 +1,37 @@
+package weatherplugin
+
+import (
+	"github.com/lampjaw/mutterblack.discord"
+)
+
+func init() {
+	mutterblack.RegisterCatalog("en", mutterblack.Catalog{
+		"weather.command.current":         "Get the current weather condition.",
+		"weather.command.forecast":        "Get the forecasted weather conditions.",
+		"weather.unavailable":             "Weather is temporarily unavailable, please try again in a few minutes.",
+		"weather.current.summary":         "Currently %s and %s with a high of %s and a low of %s.",
+		"weather.field.wind-speed":        "Wind Speed",
+		"weather.field.wind-chill":        "Wind Chill",
+		"weather.field.humidity":          "Humidity",
+		"weather.field.heat-index":        "Heat Index",
+		"weather.value.wind-speed":        "%0.1f MpH",
+		"weather.value.wind-speed-metric": "%0.1f km/h",
+		"weather.setting.units":           "Units to show temperature and wind speed in.",
+		"weather.argument.location":       "A city name, city and state, or postal code.",
+	})
+
+	mutterblack.RegisterCatalog("es", mutterblack.Catalog{
+		"weather.command.current":         "Muestra las condiciones meteorológicas actuales.",
+		"weather.command.forecast":        "Muestra el pronóstico del tiempo.",
+		"weather.unavailable":             "El tiempo no está disponible temporalmente, inténtalo de nuevo en unos minutos.",
+		"weather.current.summary":         "Actualmente %s y %s, con una máxima de %s y una mínima de %s.",
+		"weather.field.wind-speed":        "Velocidad del viento",
+		"weather.field.wind-chill":        "Sensación térmica",
+		"weather.field.humidity":          "Humedad",
+		"weather.field.heat-index":        "Índice de calor",
+		"weather.value.wind-speed":        "%0.1f mph",
+		"weather.value.wind-speed-metric": "%0.1f km/h",
+		"weather.setting.units":           "Unidades de la temperatura y la velocidad del viento.",
+		"weather.argument.location":       "Una ciudad, ciudad y estado, o código postal.",
+	})
+}
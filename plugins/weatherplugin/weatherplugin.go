@@ -3,7 +3,6 @@ package weatherplugin
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"sync"
 
 	"github.com/bwmarrin/discordgo"
@@ -11,6 +10,7 @@ import (
 )
 
 type weatherPlugin struct {
+	mutterblack.BasePlugin
 	sync.RWMutex
 }
 
@@ -54,11 +54,17 @@ func (p *weatherPlugin) Commands() []mutterblack.CommandDefinition {
 			},
 			Arguments: []mutterblack.CommandDefinitionArgument{
 				mutterblack.CommandDefinitionArgument{
-					Pattern: ".*",
-					Alias:   "location",
+					Pattern:     ".*",
+					Alias:       "location",
+					Type:        mutterblack.ArgumentTypeText,
+					Description: "weather.argument.location",
 				},
 			},
-			Description: "Get the current weather condition.",
+			Examples: []string{
+				"Seattle, WA",
+				"98101",
+			},
+			Description: "weather.command.current",
 			Callback:    p.runCurrentWeatherCommand,
 		},
 		mutterblack.CommandDefinition{
@@ -70,119 +76,122 @@ func (p *weatherPlugin) Commands() []mutterblack.CommandDefinition {
 			},
 			Arguments: []mutterblack.CommandDefinitionArgument{
 				mutterblack.CommandDefinitionArgument{
-					Pattern: ".*",
-					Alias:   "location",
+					Pattern:     ".*",
+					Alias:       "location",
+					Type:        mutterblack.ArgumentTypeText,
+					Description: "weather.argument.location",
 				},
 			},
-			Description: "Get the forecasted weather conditions.",
+			Examples: []string{
+				"Seattle, WA",
+				"98101",
+			},
+			Description: "weather.command.forecast",
 			Callback:    p.runForecastWeatherCommand,
 		},
 	}
 }
 
-func (p *weatherPlugin) Name() string {
-	return "Weather"
-}
-
-func (p *weatherPlugin) Load(bot *mutterblack.Bot, client *mutterblack.Discord, data []byte) error {
-	if data != nil {
-		if err := json.Unmarshal(data, p); err != nil {
-			log.Println("Error loading data", err)
-		}
-	}
-
-	return nil
-}
-
-func (p *weatherPlugin) Save() ([]byte, error) {
-	return json.Marshal(p)
-}
+const (
+	unitsBoth     = "both"
+	unitsImperial = "imperial"
+	unitsMetric   = "metric"
+)
 
-func (p *weatherPlugin) Help(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, detailed bool) []string {
-	return []string{
-		mutterblack.CommandHelp(client, "w", "<location>", "Returns the current weather.")[0],
-		mutterblack.CommandHelp(client, "wf", "<location>", "Returns a 5 day forecast.")[0],
+func (p *weatherPlugin) Settings() []mutterblack.Setting {
+	return []mutterblack.Setting{
+		mutterblack.Setting{
+			Key:         "units",
+			Type:        mutterblack.SettingTypeChoice,
+			Default:     unitsBoth,
+			Description: "weather.setting.units",
+			Choices:     []string{unitsBoth, unitsImperial, unitsMetric},
+		},
 	}
 }
 
-func (p *weatherPlugin) Message(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) {
-
-}
-
-func (p *weatherPlugin) Stats(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message) []string {
-	return nil
-}
-
 func New() mutterblack.Plugin {
-	return &weatherPlugin{}
+	return &weatherPlugin{
+		BasePlugin: mutterblack.NewBasePlugin("Weather"),
+	}
 }
 
 func (p *weatherPlugin) runCurrentWeatherCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, args map[string]string, trigger string) {
-	resp, err := mutterblack.SendCoreCommand("weather", "current", args)
+	l := bot.Localizer(message)
+
+	result, err := bot.Core.CommandResult("weather", "current", args)
 
 	if err != nil {
 		p.RLock()
-		client.SendMessage(message.Channel(), fmt.Sprintf("%s", err))
+		client.SendMessage(message.Channel(), mutterblack.CoreErrorMessage(l, err, "weather.unavailable"))
 		p.RUnlock()
 		return
 	}
 
 	var weather CurrentWeather
-	json.Unmarshal(resp, &weather)
+	json.Unmarshal(result.Data, &weather)
+
+	units := bot.Settings(p, message).String("units")
 
 	embed := &discordgo.MessageEmbed{
 		Author: &discordgo.MessageEmbedAuthor{
 			Name: weather.City + ", " + weather.Region + " - " + weather.Country,
 		},
 		Color:       0x070707,
-		Description: fmt.Sprintf("Currently %s and %s with a high of %s and a low of %s.", convertToTempString(weather.Temperature), weather.Condition, convertToTempString(weather.ForecastHigh), convertToTempString(weather.ForecastLow)),
+		Description: l.T("weather.current.summary", convertToTempString(weather.Temperature, units), weather.Condition, convertToTempString(weather.ForecastHigh, units), convertToTempString(weather.ForecastLow, units)),
 		Fields: []*discordgo.MessageEmbedField{
 			&discordgo.MessageEmbedField{
-				Name:   "Wind Speed",
-				Value:  fmt.Sprintf("%0.1f MpH", weather.WindSpeed),
+				Name:   l.T("weather.field.wind-speed"),
+				Value:  convertToSpeedString(l, weather.WindSpeed, units),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Wind Chill",
-				Value:  convertToTempString(weather.WindChill),
+				Name:   l.T("weather.field.wind-chill"),
+				Value:  convertToTempString(weather.WindChill, units),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Humidity",
+				Name:   l.T("weather.field.humidity"),
 				Value:  fmt.Sprintf("%d%%", weather.Humidity),
 				Inline: true,
 			},
 			&discordgo.MessageEmbedField{
-				Name:   "Heat Index",
-				Value:  convertToTempString(weather.HeatIndex),
+				Name:   l.T("weather.field.heat-index"),
+				Value:  convertToTempString(weather.HeatIndex, units),
 				Inline: true,
 			},
 		},
 	}
 
+	result.AddStaleFooter(embed, l)
+
 	p.RLock()
 	client.SendEmbedMessage(message.Channel(), embed)
 	p.RUnlock()
 }
 
 func (p *weatherPlugin) runForecastWeatherCommand(bot *mutterblack.Bot, client *mutterblack.Discord, message mutterblack.Message, args map[string]string, trigger string) {
-	resp, err := mutterblack.SendCoreCommand("weather", "forecast", args)
+	l := bot.Localizer(message)
+
+	result, err := bot.Core.CommandResult("weather", "forecast", args)
 
 	if err != nil {
 		p.RLock()
-		client.SendMessage(message.Channel(), fmt.Sprintf("%s", err))
+		client.SendMessage(message.Channel(), mutterblack.CoreErrorMessage(l, err, "weather.unavailable"))
 		p.RUnlock()
 		return
 	}
 
 	var weather ForecastWeather
-	json.Unmarshal(resp, &weather)
+	json.Unmarshal(result.Data, &weather)
+
+	units := bot.Settings(p, message).String("units")
 
 	var messageFields []*discordgo.MessageEmbedField
 	for i := 0; i < 5; i++ {
 		var field = &discordgo.MessageEmbedField{
 			Name:   weather.Forecast[i].Date,
-			Value:  createWeatherDay(weather.Forecast[i]),
+			Value:  createWeatherDay(weather.Forecast[i], units),
 			Inline: false,
 		}
 		messageFields = append(messageFields, field)
@@ -196,22 +205,38 @@ func (p *weatherPlugin) runForecastWeatherCommand(bot *mutterblack.Bot, client *
 		Fields: messageFields,
 	}
 
+	result.AddStaleFooter(embed, l)
+
 	p.RLock()
 	client.SendEmbedMessage(message.Channel(), embed)
 	p.RUnlock()
 }
 
-func createWeatherDay(d WeatherDay) string {
-	var temperatureHigh = convertToTempString(d.High)
-	var temperatureLow = convertToTempString(d.Low)
+func createWeatherDay(d WeatherDay, units string) string {
+	var temperatureHigh = convertToTempString(d.High, units)
+	var temperatureLow = convertToTempString(d.Low, units)
 	return fmt.Sprintf("%s: %s / %s - %s", d.Day, temperatureHigh, temperatureLow, d.Condition)
 }
 
-func convertToTempString(temp int) string {
+func convertToTempString(temp int, units string) string {
 	var tempCelsius = convertToCelsius(temp)
+	switch units {
+	case unitsImperial:
+		return fmt.Sprintf("%d °F", temp)
+	case unitsMetric:
+		return fmt.Sprintf("%d °C", int32(tempCelsius))
+	}
 	return fmt.Sprintf("%d °F (%d °C)", temp, int32(tempCelsius))
 }
 
 func convertToCelsius(temp int) float32 {
 	return (float32(temp) - 32) / 1.8
 }
+
+// convertToSpeedString formats a wind speed given in mph, wind speed has always been shown in mph alone so only metric units change it.
+func convertToSpeedString(l *mutterblack.Localizer, speed float32, units string) string {
+	if units == unitsMetric {
+		return l.T("weather.value.wind-speed-metric", speed*1.609344)
+	}
+	return l.T("weather.value.wind-speed", speed)
+}
@@ -0,0 +1,210 @@
+package mutterblack
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// SettingType is the kind of value a plugin setting holds.
+type SettingType string
+
+const (
+	// SettingTypeText is free form text.
+	SettingTypeText SettingType = "text"
+	// SettingTypeNumber is a whole number.
+	SettingTypeNumber SettingType = "number"
+	// SettingTypeBool is on or off.
+	SettingTypeBool SettingType = "bool"
+	// SettingTypeChoice is one of the setting's Choices.
+	SettingTypeChoice SettingType = "choice"
+	// SettingTypeChannel is a channel of the guild, stored as its ID.
+	SettingTypeChannel SettingType = "channel"
+	// SettingTypeRole is a role of the guild, stored as its ID.
+	SettingTypeRole SettingType = "role"
+)
+
+// Setting describes an option a plugin offers to guilds, moderators change it with ?configure <plugin> set.
+type Setting struct {
+	Key  string
+	Type SettingType
+	// Default is the value used until a guild sets one, in the stored form: an ID for channels and roles, "true" or "false" for bools.
+	Default string
+	// Description is shown by ?configure <plugin> show, it may be a catalog key.
+	Description string
+	// Choices are the values a SettingTypeChoice accepts.
+	Choices []string
+	// Validate optionally checks a parsed value, the message of its error is shown to the moderator and may be a catalog key.
+	Validate func(value string) error
+}
+
+// SettingsProvider is implemented by plugins with settings that guilds can change.
+type SettingsProvider interface {
+	Settings() []Setting
+}
+
+// PluginSettings returns the settings declared by a plugin, or nil if it has none.
+func PluginSettings(plugin Plugin) []Setting {
+	if provider, ok := plugin.(SettingsProvider); ok {
+		return provider.Settings()
+	}
+	return nil
+}
+
+// findSetting returns the setting of plugin called key, ignoring case.
+func findSetting(plugin Plugin, key string) (Setting, bool) {
+	for _, setting := range PluginSettings(plugin) {
+		if strings.EqualFold(setting.Key, key) {
+			return setting, true
+		}
+	}
+	return Setting{}, false
+}
+
+// parse converts what a moderator typed into the stored form of the setting, errors are translated by l.
+func (s Setting) parse(client *Discord, guildID string, l *Localizer, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch s.Type {
+	case SettingTypeNumber:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", errors.New(l.T("configure.setting.invalid-number"))
+		}
+	case SettingTypeBool:
+		switch strings.ToLower(value) {
+		case "on", "true", "yes", "1":
+			value = "true"
+		case "off", "false", "no", "0":
+			value = "false"
+		default:
+			return "", errors.New(l.T("configure.setting.invalid-bool"))
+		}
+	case SettingTypeChoice:
+		matched := false
+		for _, choice := range s.Choices {
+			if strings.EqualFold(choice, value) {
+				value = choice
+				matched = true
+			}
+		}
+		if !matched {
+			return "", errors.New(l.T("configure.setting.invalid-choice", strings.Join(s.Choices, ", ")))
+		}
+	case SettingTypeChannel:
+		channel := client.FindGuildChannel(guildID, trimMention(value, "<#"))
+		if channel == nil {
+			return "", errors.New(l.T("configure.unknown-channel", value))
+		}
+		value = channel.ID
+	case SettingTypeRole:
+		role := client.FindGuildRole(guildID, trimMention(value, "<@&"))
+		if role == nil {
+			return "", errors.New(l.T("configure.unknown-role", value))
+		}
+		value = role.ID
+	}
+
+	if s.Validate != nil {
+		if err := s.Validate(value); err != nil {
+			return "", errors.New(l.T(err.Error()))
+		}
+	}
+
+	return value, nil
+}
+
+// display formats a stored value for a moderator.
+func (s Setting) display(client *Discord, guildID string, l *Localizer, value string) string {
+	if value == "" {
+		return l.T("configure.setting.unset")
+	}
+
+	switch s.Type {
+	case SettingTypeBool:
+		if value == "true" {
+			return l.T("configure.setting.on")
+		}
+		return l.T("configure.setting.off")
+	case SettingTypeChannel:
+		return "<#" + value + ">"
+	case SettingTypeRole:
+		if role := client.FindGuildRole(guildID, value); role != nil {
+			return "@" + role.Name
+		}
+	}
+	return "`" + value + "`"
+}
+
+// pluginSetting returns the value a guild set for a plugin setting, if any.
+func (c *GuildConfiguration) pluginSetting(pluginName string, key string) (string, bool) {
+	value, ok := c.PluginSettings[pluginName][key]
+	return value, ok
+}
+
+// setPluginSetting stores the value a guild set for a plugin setting.
+func (c *GuildConfiguration) setPluginSetting(pluginName string, key string, value string) {
+	if c.PluginSettings == nil {
+		c.PluginSettings = make(map[string]map[string]string)
+	}
+	if c.PluginSettings[pluginName] == nil {
+		c.PluginSettings[pluginName] = make(map[string]string)
+	}
+	c.PluginSettings[pluginName][key] = value
+}
+
+// resetPluginSetting removes the value a guild set for a plugin setting, so the default is used again.
+func (c *GuildConfiguration) resetPluginSetting(pluginName string, key string) {
+	delete(c.PluginSettings[pluginName], key)
+	if len(c.PluginSettings[pluginName]) == 0 {
+		delete(c.PluginSettings, pluginName)
+	}
+}
+
+// Settings is the typed view of a plugin's settings in one guild.
+type Settings struct {
+	values map[string]string
+}
+
+// Settings returns the settings of plugin in the guild a message was sent in.
+// Private messages, and guilds whose configuration can't be loaded, get the defaults.
+func (b *Bot) Settings(plugin Plugin, message Message) *Settings {
+	settings := PluginSettings(plugin)
+
+	s := &Settings{values: map[string]string{}}
+	for _, setting := range settings {
+		s.values[setting.Key] = setting.Default
+	}
+
+	if message == nil || b.Client.IsPrivate(message) {
+		return s
+	}
+
+	config := b.Client.messageGuildConfiguration(message)
+	if config == nil {
+		return s
+	}
+
+	for _, setting := range settings {
+		if value, ok := config.pluginSetting(plugin.Name(), setting.Key); ok {
+			s.values[setting.Key] = value
+		}
+	}
+
+	return s
+}
+
+// String returns the value of a setting, channels and roles are returned as their ID.
+func (s *Settings) String(key string) string {
+	return s.values[key]
+}
+
+// Int returns the value of a number setting, or 0 if it isn't one.
+func (s *Settings) Int(key string) int {
+	value, _ := strconv.Atoi(s.values[key])
+	return value
+}
+
+// Bool returns the value of a bool setting.
+func (s *Settings) Bool(key string) bool {
+	return s.values[key] == "true"
+}
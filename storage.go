@@ -0,0 +1,322 @@
+package mutterblack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// storageSuffix is added to a plugin name to name the data holding its storage, next to the data saved by Persister.
+const storageSuffix = ".storage"
+
+// storageNamespace identifies a bucket, a bucket with neither ID is the plugin's global bucket.
+type storageNamespace struct {
+	guildID string
+	userID  string
+}
+
+// pluginStore holds every bucket of one plugin.
+type pluginStore struct {
+	sync.RWMutex
+	name    string
+	buckets map[storageNamespace]map[string]json.RawMessage
+	dirty   bool
+	// changed is called whenever a bucket changes, so the change gets saved.
+	changed func()
+}
+
+// storedBuckets is how a plugin's storage is saved.
+type storedBuckets struct {
+	Global  map[string]json.RawMessage                       `json:"global,omitempty"`
+	Guilds  map[string]map[string]json.RawMessage            `json:"guilds,omitempty"`
+	Users   map[string]map[string]json.RawMessage            `json:"users,omitempty"`
+	Members map[string]map[string]map[string]json.RawMessage `json:"members,omitempty"`
+}
+
+func newPluginStore(name string) *pluginStore {
+	return &pluginStore{
+		name:    name,
+		buckets: make(map[storageNamespace]map[string]json.RawMessage),
+	}
+}
+
+func (s *pluginStore) load(data []byte) error {
+	if data == nil {
+		return nil
+	}
+
+	stored := storedBuckets{}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if len(stored.Global) > 0 {
+		s.buckets[storageNamespace{}] = stored.Global
+	}
+	for guildID, bucket := range stored.Guilds {
+		s.buckets[storageNamespace{guildID: guildID}] = bucket
+	}
+	for userID, bucket := range stored.Users {
+		s.buckets[storageNamespace{userID: userID}] = bucket
+	}
+	for guildID, members := range stored.Members {
+		for userID, bucket := range members {
+			s.buckets[storageNamespace{guildID: guildID, userID: userID}] = bucket
+		}
+	}
+	return nil
+}
+
+// markDirty records that the store changed, the caller holds the lock.
+func (s *pluginStore) markDirty() {
+	s.dirty = true
+	if s.changed != nil {
+		s.changed()
+	}
+}
+
+// save returns the stored form of every bucket, the caller holds the lock.
+func (s *pluginStore) save() ([]byte, error) {
+	stored := storedBuckets{
+		Guilds:  map[string]map[string]json.RawMessage{},
+		Users:   map[string]map[string]json.RawMessage{},
+		Members: map[string]map[string]map[string]json.RawMessage{},
+	}
+
+	for namespace, bucket := range s.buckets {
+		switch {
+		case namespace.guildID != "" && namespace.userID != "":
+			if stored.Members[namespace.guildID] == nil {
+				stored.Members[namespace.guildID] = map[string]map[string]json.RawMessage{}
+			}
+			stored.Members[namespace.guildID][namespace.userID] = bucket
+		case namespace.guildID != "":
+			stored.Guilds[namespace.guildID] = bucket
+		case namespace.userID != "":
+			stored.Users[namespace.userID] = bucket
+		default:
+			stored.Global = bucket
+		}
+	}
+
+	return json.Marshal(stored)
+}
+
+// matching returns copies of the buckets whose namespace matches.
+func (s *pluginStore) matching(match func(namespace storageNamespace) bool) map[storageNamespace]map[string]json.RawMessage {
+	s.RLock()
+	defer s.RUnlock()
+
+	buckets := map[storageNamespace]map[string]json.RawMessage{}
+	for namespace, bucket := range s.buckets {
+		if !match(namespace) {
+			continue
+		}
+		copied := make(map[string]json.RawMessage, len(bucket))
+		for key, value := range bucket {
+			copied[key] = append(json.RawMessage(nil), value...)
+		}
+		buckets[namespace] = copied
+	}
+	return buckets
+}
+
+// removeMatching deletes the buckets whose namespace matches, it returns whether any were.
+func (s *pluginStore) removeMatching(match func(namespace storageNamespace) bool) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	removed := false
+	for namespace := range s.buckets {
+		if match(namespace) {
+			delete(s.buckets, namespace)
+			removed = true
+		}
+	}
+	if removed {
+		s.markDirty()
+	}
+	return removed
+}
+
+// Storage returns the key/value storage of a plugin, it is saved with the plugin's data by Bot.Save.
+func (b *Bot) Storage(plugin Plugin) *Storage {
+	return &Storage{store: b.pluginStore(plugin.Name())}
+}
+
+// pluginStore returns the store of a plugin, loading it the first time it is used.
+func (b *Bot) pluginStore(name string) *pluginStore {
+	b.storesLock.Lock()
+	defer b.storesLock.Unlock()
+
+	if store, ok := b.stores[name]; ok {
+		return store
+	}
+
+	store := newPluginStore(name)
+	b.loadData(name+storageSuffix, store.load)
+	store.changed = b.scheduleSave
+	b.stores[name] = store
+
+	return store
+}
+
+// saveStorage writes the storage of every plugin that changed since it was last saved, it returns the first error.
+func (b *Bot) saveStorage() error {
+	var saveErr error
+	for _, store := range b.pluginStores() {
+		store.Lock()
+		if store.dirty {
+			data, err := store.save()
+			if err == nil {
+				err = b.writeData(store.name+storageSuffix, data)
+			}
+			if err != nil {
+				log.Printf("Error saving storage of plugin %s. %v", store.name, err)
+				if saveErr == nil {
+					saveErr = fmt.Errorf("%s: %v", store.name+storageSuffix, err)
+				}
+			} else {
+				store.dirty = false
+			}
+		}
+		store.Unlock()
+	}
+	return saveErr
+}
+
+// pluginStores returns every store that has been loaded.
+func (b *Bot) pluginStores() []*pluginStore {
+	b.storesLock.Lock()
+	defer b.storesLock.Unlock()
+
+	stores := []*pluginStore{}
+	for _, store := range b.stores {
+		stores = append(stores, store)
+	}
+	return stores
+}
+
+// Storage is the key/value storage of a plugin.
+// It is split into buckets for the whole bot, each guild, each user and each member of a guild, so data can be found and removed by guild or user.
+type Storage struct {
+	store *pluginStore
+}
+
+// Global returns the bucket for data that belongs to no guild or user.
+func (s *Storage) Global() *Bucket {
+	return &Bucket{store: s.store}
+}
+
+// Guild returns the bucket for data of a guild.
+func (s *Storage) Guild(guildID string) *Bucket {
+	return &Bucket{store: s.store, namespace: storageNamespace{guildID: guildID}}
+}
+
+// User returns the bucket for data of a user, wherever they use the bot.
+func (s *Storage) User(userID string) *Bucket {
+	return &Bucket{store: s.store, namespace: storageNamespace{userID: userID}}
+}
+
+// Member returns the bucket for data of a user in one guild.
+func (s *Storage) Member(guildID, userID string) *Bucket {
+	return &Bucket{store: s.store, namespace: storageNamespace{guildID: guildID, userID: userID}}
+}
+
+// Bucket is a set of keys in a plugin's storage, values are JSON.
+type Bucket struct {
+	store     *pluginStore
+	namespace storageNamespace
+}
+
+// Get returns the value of key.
+func (b *Bucket) Get(key string) (json.RawMessage, bool) {
+	b.store.RLock()
+	defer b.store.RUnlock()
+
+	value, ok := b.store.buckets[b.namespace][key]
+	if !ok {
+		return nil, false
+	}
+	return append(json.RawMessage(nil), value...), true
+}
+
+// Put sets the value of key, the value must be valid JSON.
+func (b *Bucket) Put(key string, value json.RawMessage) error {
+	if !json.Valid(value) {
+		return fmt.Errorf("invalid JSON value for %s", key)
+	}
+
+	b.store.Lock()
+	defer b.store.Unlock()
+
+	bucket, ok := b.store.buckets[b.namespace]
+	if !ok {
+		bucket = make(map[string]json.RawMessage)
+		b.store.buckets[b.namespace] = bucket
+	}
+	bucket[key] = append(json.RawMessage(nil), value...)
+	b.store.markDirty()
+
+	return nil
+}
+
+// Delete removes key, removing a key that isn't set does nothing.
+func (b *Bucket) Delete(key string) {
+	b.store.Lock()
+	defer b.store.Unlock()
+
+	bucket, ok := b.store.buckets[b.namespace]
+	if !ok {
+		return
+	}
+	if _, ok := bucket[key]; !ok {
+		return
+	}
+
+	delete(bucket, key)
+	if len(bucket) == 0 {
+		delete(b.store.buckets, b.namespace)
+	}
+	b.store.markDirty()
+}
+
+// List returns the keys starting with prefix in sorted order, an empty prefix lists every key.
+func (b *Bucket) List(prefix string) []string {
+	b.store.RLock()
+	defer b.store.RUnlock()
+
+	keys := []string{}
+	for key := range b.store.buckets[b.namespace] {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// GetJSON decodes the value of key into v, it returns false if the key isn't set.
+func (b *Bucket) GetJSON(key string, v interface{}) (bool, error) {
+	value, ok := b.Get(key)
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(value, v)
+}
+
+// PutJSON encodes v as the value of key.
+func (b *Bucket) PutJSON(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, value)
+}